@@ -0,0 +1 @@
+drop table public.outbox;
\ No newline at end of file
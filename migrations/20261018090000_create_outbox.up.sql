@@ -0,0 +1,15 @@
+CREATE TABLE public.outbox (
+	id BIGSERIAL NOT NULL,
+	event_id text NOT NULL,
+	event_name text NOT NULL,
+	payload jsonb NOT NULL,
+	occurred_at timestamp NOT NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	published_at timestamp NULL,
+	attempts int4 DEFAULT 0 NOT NULL,
+	last_error text NULL,
+	CONSTRAINT outbox_pkey PRIMARY KEY (id),
+	CONSTRAINT outbox_event_id_unique UNIQUE (event_id)
+);
+
+CREATE INDEX outbox_unpublished_idx ON public.outbox (id) WHERE published_at IS NULL;
@@ -0,0 +1 @@
+drop table public.inbox;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE public.inbox (
+	handler_name text NOT NULL,
+	event_id text NOT NULL,
+	event_name text NOT NULL,
+	processed_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	CONSTRAINT inbox_pkey PRIMARY KEY (handler_name, event_id)
+);
@@ -0,0 +1 @@
+drop table public.dead_letters;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE public.dead_letters (
+	id BIGSERIAL NOT NULL,
+	event_id text NOT NULL,
+	event_name text NOT NULL,
+	handler_name text NOT NULL,
+	payload jsonb NOT NULL,
+	error text NOT NULL,
+	attempts int4 NOT NULL,
+	failed_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	replayed_at timestamp NULL,
+	CONSTRAINT dead_letters_pkey PRIMARY KEY (id)
+);
@@ -0,0 +1 @@
+drop table public.event_bus_events;
\ No newline at end of file
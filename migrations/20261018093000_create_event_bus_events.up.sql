@@ -0,0 +1,9 @@
+CREATE TABLE public.event_bus_events (
+	id BIGSERIAL NOT NULL,
+	event_id text NOT NULL,
+	event_name text NOT NULL,
+	payload jsonb NOT NULL,
+	occurred_at timestamp NOT NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	CONSTRAINT event_bus_events_pkey PRIMARY KEY (id)
+);
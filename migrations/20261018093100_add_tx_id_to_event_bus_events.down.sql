@@ -0,0 +1,3 @@
+drop index public.event_bus_events_created_at_idx;
+drop index public.event_bus_events_tx_id_idx;
+alter table public.event_bus_events drop column tx_id;
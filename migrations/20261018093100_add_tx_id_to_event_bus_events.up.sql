@@ -0,0 +1,5 @@
+-- tx_id ของแถวเดิมจะเป็น transaction ของ migration นี้ทั้งหมด
+ALTER TABLE public.event_bus_events ADD COLUMN tx_id xid8 DEFAULT pg_current_xact_id() NOT NULL;
+
+CREATE INDEX event_bus_events_tx_id_idx ON public.event_bus_events (tx_id, id);
+CREATE INDEX event_bus_events_created_at_idx ON public.event_bus_events (created_at);
@@ -0,0 +1 @@
+drop table public.event_log;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE public.event_log (
+	id BIGSERIAL NOT NULL,
+	event_id text NOT NULL,
+	event_name text NOT NULL,
+	payload jsonb NOT NULL,
+	occurred_at timestamp NOT NULL,
+	recorded_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	CONSTRAINT event_log_pkey PRIMARY KEY (id),
+	CONSTRAINT event_log_event_id_unique UNIQUE (event_id)
+);
+
+CREATE INDEX event_log_event_name_occurred_at_idx ON public.event_log (event_name, occurred_at);
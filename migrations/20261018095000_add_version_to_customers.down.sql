@@ -0,0 +1 @@
+ALTER TABLE public.customers DROP COLUMN version;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE public.customers ADD COLUMN version int4 DEFAULT 1 NOT NULL;
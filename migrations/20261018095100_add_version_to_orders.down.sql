@@ -0,0 +1 @@
+ALTER TABLE public.orders DROP COLUMN version;
\ No newline at end of file
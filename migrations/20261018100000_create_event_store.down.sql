@@ -0,0 +1,2 @@
+drop table public.event_store_snapshots;
+drop table public.event_store;
\ No newline at end of file
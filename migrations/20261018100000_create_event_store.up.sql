@@ -0,0 +1,19 @@
+CREATE TABLE public.event_store (
+	id BIGSERIAL NOT NULL,
+	stream_id text NOT NULL,
+	version int4 NOT NULL,
+	event_name text NOT NULL,
+	payload jsonb NOT NULL,
+	occurred_at timestamp NOT NULL,
+	recorded_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	CONSTRAINT event_store_pkey PRIMARY KEY (id),
+	CONSTRAINT event_store_stream_version_unique UNIQUE (stream_id, version)
+);
+
+CREATE TABLE public.event_store_snapshots (
+	stream_id text NOT NULL,
+	version int4 NOT NULL,
+	state jsonb NOT NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	CONSTRAINT event_store_snapshots_pkey PRIMARY KEY (stream_id)
+);
@@ -0,0 +1 @@
+DELETE FROM public.event_store WHERE stream_id LIKE 'customer-credit-%';
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- เปิด credit account ให้ลูกค้าที่มีอยู่แล้ว ด้วยยอด credit ปัจจุบัน
+INSERT INTO public.event_store (stream_id, version, event_name, payload, occurred_at)
+SELECT
+	'customer-credit-' || c.id,
+	1,
+	'CreditAccountOpened',
+	jsonb_build_object(
+		'Name', 'CreditAccountOpened',
+		'At', COALESCE(c.created_at, CURRENT_TIMESTAMP) AT TIME ZONE 'UTC',
+		'CustomerID', c.id,
+		'Credit', c.credit
+	),
+	COALESCE(c.created_at, CURRENT_TIMESTAMP)
+FROM public.customers c
+ON CONFLICT (stream_id, version) DO NOTHING;
@@ -0,0 +1,200 @@
+package application
+
+import (
+	"encoding/json"
+	"go-mma/application/middleware"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/eventlog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type deadLetterResponse struct {
+	ID          int64           `json:"id"`
+	EventID     string          `json:"event_id"`
+	EventName   string          `json:"event_name"`
+	HandlerName string          `json:"handler_name"`
+	Payload     json.RawMessage `json:"payload"`
+	Error       string          `json:"error"`
+	Attempts    int             `json:"attempts"`
+	FailedAt    time.Time       `json:"failed_at"`
+	ReplayedAt  *time.Time      `json:"replayed_at"`
+}
+
+func newDeadLetterResponse(dl *eventbus.DeadLetter) deadLetterResponse {
+	return deadLetterResponse{
+		ID:          dl.ID,
+		EventID:     dl.EventID,
+		EventName:   string(dl.EventName),
+		HandlerName: dl.HandlerName,
+		Payload:     json.RawMessage(dl.Payload),
+		Error:       dl.Error,
+		Attempts:    dl.Attempts,
+		FailedAt:    dl.FailedAt,
+		ReplayedAt:  dl.ReplayedAt,
+	}
+}
+
+type eventLogResponse struct {
+	ID         int64           `json:"id"`
+	EventID    string          `json:"event_id"`
+	EventName  string          `json:"event_name"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	RecordedAt time.Time       `json:"recorded_at"`
+}
+
+func newEventLogResponse(e *eventlog.Entry) eventLogResponse {
+	return eventLogResponse{
+		ID:         e.ID,
+		EventID:    e.EventID,
+		EventName:  string(e.EventName),
+		Payload:    json.RawMessage(e.Payload),
+		OccurredAt: e.OccurredAt,
+		RecordedAt: e.RecordedAt,
+	}
+}
+
+type replayEventsRequest struct {
+	HandlerName string     `json:"handler_name"`
+	EventName   string     `json:"event_name"`
+	EventID     string     `json:"event_id"`
+	From        *time.Time `json:"from"`
+	To          *time.Time `json:"to"`
+}
+
+type replayEventsResponse struct {
+	Replayed int `json:"replayed"`
+}
+
+// registerAdminRoutes เพิ่ม endpoint สำหรับดูแลระบบ ใช้ได้เฉพาะ role admin
+func (app *Application) registerAdminRoutes() {
+	admin := app.httpServer.Group("/api/admin")
+	admin.Use(middleware.RequireRole(roleAdmin))
+
+	deadLetters := admin.Group("/dead-letters")
+	deadLetters.Get("", app.listDeadLettersHTTPHandler)
+	deadLetters.Post("/:id/replay", app.replayDeadLetterHTTPHandler)
+
+	events := admin.Group("/events")
+	events.Get("", app.listEventsHTTPHandler)
+	events.Post("/replay", app.replayEventsHTTPHandler)
+
+	admin.Get("/subscriptions", app.listSubscriptionsHTTPHandler)
+	admin.Get("/cache/stats", app.cacheStatsHTTPHandler)
+}
+
+func (app *Application) listDeadLettersHTTPHandler(c fiber.Ctx) error {
+	limit := fiber.Query(c, "limit", 50)
+	offset := fiber.Query(c, "offset", 0)
+	if limit <= 0 || limit > 500 {
+		return errs.InputValidationError("limit must be between 1 and 500")
+	}
+	if offset < 0 {
+		return errs.InputValidationError("offset must not be negative")
+	}
+
+	dls, err := app.deadLetterSvc.List(c.Context(), limit, offset)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]deadLetterResponse, 0, len(dls))
+	for _, dl := range dls {
+		resp = append(resp, newDeadLetterResponse(dl))
+	}
+	return c.JSON(resp)
+}
+
+func (app *Application) replayDeadLetterHTTPHandler(c fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errs.InputValidationError("invalid dead-letter id")
+	}
+
+	if err := app.deadLetterSvc.Replay(c.Context(), id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (app *Application) listEventsHTTPHandler(c fiber.Ctx) error {
+	limit := fiber.Query(c, "limit", 50)
+	offset := fiber.Query(c, "offset", 0)
+	if limit <= 0 || limit > 500 {
+		return errs.InputValidationError("limit must be between 1 and 500")
+	}
+	if offset < 0 {
+		return errs.InputValidationError("offset must not be negative")
+	}
+
+	filter := eventlog.Filter{
+		EventName: eventbus.EventName(c.Query("name")),
+		EventID:   c.Query("event_id"),
+		Limit:     limit,
+		Offset:    offset,
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return err
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return err
+	}
+
+	entries, err := app.eventLogSvc.History(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]eventLogResponse, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, newEventLogResponse(e))
+	}
+	return c.JSON(resp)
+}
+
+func (app *Application) replayEventsHTTPHandler(c fiber.Ctx) error {
+	var req replayEventsRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return errs.InputValidationError(err.Error())
+	}
+
+	replayed, err := app.eventLogSvc.Replay(c.Context(), req.HandlerName, eventlog.Filter{
+		EventName: eventbus.EventName(req.EventName),
+		EventID:   req.EventID,
+		From:      req.From,
+		To:        req.To,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(replayEventsResponse{Replayed: replayed})
+}
+
+// parseTimeQuery อ่าน query string ในรูปแบบ RFC 3339 คืน nil ถ้าไม่ได้ส่งมา
+func parseTimeQuery(c fiber.Ctx, key string) (*time.Time, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, errs.InputValidationError(key + " must be an RFC 3339 timestamp")
+	}
+	return &t, nil
+}
+
+func (app *Application) listSubscriptionsHTTPHandler(c fiber.Ctx) error {
+	return c.JSON(app.eventBus.Subscriptions())
+}
+
+func (app *Application) cacheStatsHTTPHandler(c fiber.Ctx) error {
+	return c.JSON(app.queryCache.Stats())
+}
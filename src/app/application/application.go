@@ -1,44 +1,171 @@
 package application
 
 import (
+	"context"
 	"fmt"
 	"go-mma/config"
+	"go-mma/shared/common/cache"
+	"go-mma/shared/common/deadletter"
 	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/eventlog"
 	"go-mma/shared/common/logger"
+	"go-mma/shared/common/mediator"
 	"go-mma/shared/common/module"
+	"go-mma/shared/common/outbox"
+	"go-mma/shared/common/pgeventbus"
 	"go-mma/shared/common/registry"
+	"go-mma/shared/common/storage/sqldb/transactor"
 )
 
+// eventBusStarter คือ event bus ที่ต้องเปิด connection ของตัวเองก่อนใช้งาน
+type eventBusStarter interface {
+	Start() error
+}
+
 type Application struct {
 	config          config.Config
 	httpServer      HTTPServer
+	internalServer  HTTPServer // nil คือไม่เปิดให้ process อื่นเรียก handler ผ่าน mediator
 	serviceRegistry registry.ServiceRegistry
 	eventBus        eventbus.EventBus
+	transactor      transactor.Transactor
+	mediator        *mediator.Mediator
+	queryCache      cache.Cache
+	outboxRelay     *outbox.Relay
+	deadLetterSvc   deadletter.Service
+	eventLogSvc     eventlog.Service
 }
 
-func New(config config.Config) *Application {
-	return &Application{
+func New(config config.Config, mCtx *module.ModuleContext) (*Application, error) {
+	// behavior ที่ครอบทุก request ที่ส่งผ่าน mediator โมดูลและ endpoint ใช้ตัวเดียวกันผ่าน mCtx.Mediator
+	queryCache := cache.NewLRU(config.QueryCacheSize)
+	med := mediator.New()
+	err := med.Use(
+		mediator.Recovery(),
+		mediator.Logging(),
+		mediator.Authorization(),
+		mediator.Validation(),
+		mediator.Caching(queryCache),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up mediator: %w", err)
+	}
+	mCtx.Mediator = med
+	// code เดิมที่ยังเรียก mediator.Register/Send ระดับ package ใช้ instance เดียวกัน
+	mediator.SetDefault(med)
+	mCtx.QueryCache = queryCache
+
+	deadLetters := deadletter.NewStore(mCtx.DBCtx)
+	busOpts := []eventbus.Option{
+		eventbus.WithCodec(mCtx.Codec),
+		eventbus.WithDeadLetterStore(deadLetters),
+		eventbus.WithMiddleware(
+			eventbus.Recovery(),
+			eventbus.Tracing(),
+			eventbus.Logging(),
+		),
+	}
+	if config.EventBusWorkers > 0 {
+		busOpts = append(busOpts, eventbus.WithWorkerPool(eventbus.WorkerPoolConfig{
+			Workers:      config.EventBusWorkers,
+			QueueSize:    config.EventBusQueueSize,
+			Backpressure: eventbus.Backpressure(config.EventBusBackpressure),
+		}))
+	}
+	eventLog := eventlog.NewStore(mCtx.DBCtx, mCtx.Codec)
+	eventBus := eventlog.NewBus(newEventBus(config, mCtx, busOpts...), eventLog)
+
+	app := &Application{
 		config:          config,
 		httpServer:      newHTTPServer(config),
 		serviceRegistry: registry.NewServiceRegistry(),
-		eventBus:        eventbus.NewInMemoryEventBus(),
+		eventBus:        eventBus,
+		transactor:      mCtx.Transactor,
+		mediator:        med,
+		queryCache:      queryCache,
+		outboxRelay: outbox.NewRelay(mCtx.Transactor, mCtx.DBCtx, mCtx.Codec, eventBus,
+			outbox.WithPollInterval(config.OutboxInterval),
+		),
+		deadLetterSvc: deadletter.NewService(deadLetters, eventBus, mCtx.Codec),
+		eventLogSvc:   eventlog.NewService(eventLog, eventBus, mCtx.Codec),
+	}
+	if config.InternalHTTPPort > 0 {
+		app.internalServer = newInternalHTTPServer(config)
+	}
+
+	app.registerAdminRoutes()
+	app.registerRemoteRoutes()
+
+	return app, nil
+}
+
+func newEventBus(cfg config.Config, mCtx *module.ModuleContext, opts ...eventbus.Option) eventbus.EventBus {
+	switch cfg.EventBus {
+	case config.EventBusPostgres:
+		return pgeventbus.New(cfg.DSN, mCtx.DBCtx, mCtx.Codec, opts, pgeventbus.WithRetention(cfg.EventBusRetention))
+	default:
+		return eventbus.NewInMemoryEventBus(opts...)
 	}
 }
 
 func (app *Application) Run() error {
+	if s, ok := app.eventBus.(eventBusStarter); ok {
+		if err := s.Start(); err != nil {
+			return fmt.Errorf("failed to start event bus: %w", err)
+		}
+	}
+
+	// ลงทะเบียน handler ได้เฉพาะตอน RegisterModules เท่านั้น
+	app.mediator.Freeze()
+
 	app.httpServer.Start()
+	if app.internalServer != nil {
+		app.internalServer.Start()
+	}
+	app.outboxRelay.Start()
 
 	return nil
 }
 
 func (app *Application) Shutdown() error {
+	// ทุกขั้นใช้ deadline เดียวกัน การปิดทั้งหมดจึงไม่เกิน GracefulTimeout
+	ctx, cancel := context.WithTimeout(context.Background(), app.config.GracefulTimeout)
+	defer cancel()
+
 	// Gracefully close fiber server
 	logger.Log.Info("Shutting down server")
-	if err := app.httpServer.Shutdown(); err != nil {
+	if err := app.httpServer.Shutdown(ctx); err != nil {
 		logger.Log.Fatal(fmt.Sprintf("Error shutting down server: %v", err))
 	}
+	if app.internalServer != nil {
+		if err := app.internalServer.Shutdown(ctx); err != nil {
+			logger.Log.Fatal(fmt.Sprintf("Error shutting down internal server: %v", err))
+		}
+	}
 	logger.Log.Info("Server stopped")
 
+	if err := app.outboxRelay.Stop(ctx); err != nil {
+		logger.Log.Warn(fmt.Sprintf("Outbox relay not stopped: %v", err))
+	} else {
+		logger.Log.Info("Outbox relay stopped")
+	}
+
+	// รองานที่ค้างใน background ภายในเวลาที่เหลือ
+	// ปิด event bus ก่อน เพราะ handler ที่ยังทำงานอยู่ (เช่นที่ห่อด้วย inbox) ยังต้องเปิด transaction
+	// ส่วน post-commit hook ไม่ได้ publish เข้า event bus โดยตรง (integration event ผ่าน outbox)
+
+	if abandoned, err := app.eventBus.Drain(ctx); err != nil {
+		logger.Log.Warn(fmt.Sprintf("Event bus not drained: %d handler(s) abandoned: %v", abandoned, err))
+	} else {
+		logger.Log.Info("Event bus drained")
+	}
+
+	if abandoned, err := app.transactor.Drain(ctx); err != nil {
+		logger.Log.Warn(fmt.Sprintf("Post-commit hooks not drained: %d hook(s) abandoned: %v", abandoned, err))
+	} else {
+		logger.Log.Info("Post-commit hooks drained")
+	}
+
 	return nil
 }
 
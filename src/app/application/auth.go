@@ -0,0 +1,21 @@
+package application
+
+import (
+	"go-mma/shared/common/auth"
+	"go-mma/shared/contract/customercontract"
+)
+
+const roleAdmin = "admin"
+
+// rolePermissions กำหนดสิทธิ์ของแต่ละ role ที่ได้รับจาก API gateway
+var rolePermissions = auth.RolePermissions{
+	roleAdmin: {auth.AllPermissions},
+	"staff": {
+		"customer:create",
+		"order:create",
+		"order:cancel",
+		// order ตัดและคืน credit ของลูกค้าด้วยสิทธิ์ของผู้ที่สร้างหรือยกเลิก order
+		customercontract.PermissionReserveCredit,
+		customercontract.PermissionReleaseCredit,
+	},
+}
@@ -17,33 +17,37 @@ import (
 
 type HTTPServer interface {
 	Start()
-	Shutdown() error
+	// Shutdown รอ request ที่ค้างอยู่ให้เสร็จภายในเวลาของ ctx
+	Shutdown(ctx context.Context) error
 	Group(prefix string) fiber.Router
 }
 
 type httpServer struct {
 	config config.Config
+	port   int
 	app    *fiber.App
 }
 
 func newHTTPServer(config config.Config) HTTPServer {
 	return &httpServer{
 		config: config,
-		app:    newFiber(),
+		port:   config.HTTPPort,
+		app:    newFiber(config),
 	}
 }
 
-func newFiber() *fiber.App {
+func newFiber(config config.Config) *fiber.App {
 	app := fiber.New(fiber.Config{
 		AppName: fmt.Sprintf("Go MMA version %s", build.Version),
 	})
 
 	// global middleware
-	app.Use(cors.New())                 // CORS ลำดับแรก เพื่อให้ OPTIONS request ผ่านได้เสมอ
-	app.Use(requestid.New())            // สร้าง request id ใน request header สำหรับการ debug
-	app.Use(recover.New())              // auto-recovers from panic (internal only)
-	app.Use(middleware.RequestLogger()) // logs HTTP request
-	app.Use(middleware.ResponseError()) // จัดการ error จาก Handler Layer หากเกิดขึ้น
+	app.Use(cors.New())                                                     // CORS ลำดับแรก เพื่อให้ OPTIONS request ผ่านได้เสมอ
+	app.Use(requestid.New())                                                // สร้าง request id ใน request header สำหรับการ debug
+	app.Use(recover.New())                                                  // auto-recovers from panic (internal only)
+	app.Use(middleware.RequestLogger())                                     // logs HTTP request
+	app.Use(middleware.ResponseError())                                     // จัดการ error จาก Handler Layer หากเกิดขึ้น
+	app.Use(middleware.Authenticate(rolePermissions, config.GatewaySecret)) // แนบ principal ของผู้เรียกไว้ใน context
 
 	app.Get("/", func(c fiber.Ctx) error {
 		return c.JSON(map[string]string{"version": build.Version, "time": build.Time})
@@ -52,18 +56,37 @@ func newFiber() *fiber.App {
 	return app
 }
 
+// newInternalHTTPServer รับ request จาก process อื่นของระบบบน InternalHTTPPort แยกจาก API ที่เปิดผ่าน gateway
+// ทุก request ต้องมี X-Service-Secret ตรงกับ InternalSecret
+func newInternalHTTPServer(config config.Config) HTTPServer {
+	app := fiber.New(fiber.Config{
+		AppName: fmt.Sprintf("Go MMA internal version %s", build.Version),
+	})
+
+	app.Use(requestid.New())
+	app.Use(recover.New())
+	app.Use(middleware.RequestLogger())
+	app.Use(middleware.ResponseError())
+	app.Use(middleware.RequireServiceSecret(config.InternalSecret))
+	app.Use(middleware.Authenticate(rolePermissions, config.InternalSecret))
+
+	return &httpServer{
+		config: config,
+		port:   config.InternalHTTPPort,
+		app:    app,
+	}
+}
+
 func (s *httpServer) Start() {
 	go func() {
-		logger.Log.Info(fmt.Sprintf("Starting server on port %d", s.config.HTTPPort))
-		if err := s.app.Listen(fmt.Sprintf(":%d", s.config.HTTPPort)); err != nil && err != http.ErrServerClosed {
+		logger.Log.Info(fmt.Sprintf("Starting server on port %d", s.port))
+		if err := s.app.Listen(fmt.Sprintf(":%d", s.port)); err != nil && err != http.ErrServerClosed {
 			logger.Log.Fatal(fmt.Sprintf("Error starting server: %v", err))
 		}
 	}()
 }
 
-func (s *httpServer) Shutdown() error {
-	ctx, cancel := context.WithTimeout(context.Background(), s.config.GracefulTimeout)
-	defer cancel()
+func (s *httpServer) Shutdown(ctx context.Context) error {
 	return s.app.ShutdownWithContext(ctx)
 }
 
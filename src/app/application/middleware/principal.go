@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"go-mma/shared/common/auth"
+	"go-mma/shared/common/errs"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Authenticate สร้าง principal จาก header ที่ API gateway ส่งมาหลังยืนยันตัวตนแล้ว
+// (X-User-ID, X-User-Roles คั่นด้วย comma, X-Tenant-ID) และแนบไว้ใน c.Context()
+// header เหล่านี้ client ตั้งเองได้ จึงเชื่อเฉพาะเมื่อ X-Service-Secret ตรงกับ secret
+// ถ้ามี X-User-ID แต่ secret ไม่ตรงจะตอบ 401 ถ้า secret ว่างจะไม่เชื่อ header ตัวตนเลย
+// header ตัวตนและ secret ถูกลบออกจาก request เสมอ เพื่อไม่ให้ handler ถัดไปอ่านค่าที่ยังไม่ได้ตรวจ
+// request ที่ไม่มี X-User-ID จะไม่มี principal
+func Authenticate(roles auth.RolePermissions, secret string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		userID := c.Get(auth.HeaderUserID)
+		rolesHeader := c.Get(auth.HeaderUserRoles)
+		tenantID := c.Get(auth.HeaderTenantID)
+		trusted := validServiceSecret(c, secret)
+		stripIdentityHeaders(c)
+
+		if userID == "" || secret == "" {
+			return c.Next()
+		}
+		if !trusted {
+			return errs.AuthenticationError("invalid service secret")
+		}
+
+		var userRoles []string
+		for _, r := range strings.Split(rolesHeader, ",") {
+			if r = strings.TrimSpace(r); r != "" {
+				userRoles = append(userRoles, r)
+			}
+		}
+
+		principal := &auth.Principal{
+			UserID:      userID,
+			TenantID:    tenantID,
+			Roles:       userRoles,
+			Permissions: roles.Resolve(userRoles),
+		}
+		c.SetContext(auth.ContextWithPrincipal(c.Context(), principal))
+
+		return c.Next()
+	}
+}
+
+// RequireServiceSecret อนุญาตเฉพาะ request ที่มี X-Service-Secret ตรงกับ secret ใช้กับ endpoint ภายในระบบ
+// secret ว่างจะปฏิเสธทุก request
+func RequireServiceSecret(secret string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if !validServiceSecret(c, secret) {
+			return errs.AuthenticationError("invalid service secret")
+		}
+		return c.Next()
+	}
+}
+
+func validServiceSecret(c fiber.Ctx, secret string) bool {
+	return secret != "" && subtle.ConstantTimeCompare([]byte(c.Get(auth.HeaderServiceSecret)), []byte(secret)) == 1
+}
+
+func stripIdentityHeaders(c fiber.Ctx) {
+	for _, h := range []string{auth.HeaderUserID, auth.HeaderUserRoles, auth.HeaderTenantID, auth.HeaderServiceSecret} {
+		c.Request().Header.Del(h)
+	}
+}
+
+// RequireRole อนุญาตเฉพาะ principal ที่มี role ที่กำหนด
+func RequireRole(role string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		principal, ok := auth.PrincipalFromContext(c.Context())
+		if !ok {
+			return errs.AuthenticationError("authentication required")
+		}
+		if !principal.HasRole(role) {
+			return errs.NewAuthorizationError("role " + role + " is required")
+		}
+		return c.Next()
+	}
+}
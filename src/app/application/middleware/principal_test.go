@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"go-mma/shared/common/auth"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestAuthenticateTrustsHeadersOnlyWithServiceSecret(t *testing.T) {
+	roles := auth.RolePermissions{"admin": {auth.AllPermissions}}
+
+	tests := []struct {
+		name       string
+		secret     string
+		headers    map[string]string
+		wantStatus int
+		wantUser   string
+	}{
+		{
+			name:       "no identity headers",
+			secret:     "s3cret",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "valid secret",
+			secret:     "s3cret",
+			headers:    map[string]string{auth.HeaderUserID: "u1", auth.HeaderUserRoles: "admin", auth.HeaderServiceSecret: "s3cret"},
+			wantStatus: http.StatusOK,
+			wantUser:   "u1",
+		},
+		{
+			name:       "missing secret",
+			secret:     "s3cret",
+			headers:    map[string]string{auth.HeaderUserID: "u1", auth.HeaderUserRoles: "admin"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong secret",
+			secret:     "s3cret",
+			headers:    map[string]string{auth.HeaderUserID: "u1", auth.HeaderUserRoles: "admin", auth.HeaderServiceSecret: "guess"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "secret not configured ignores identity headers",
+			headers:    map[string]string{auth.HeaderUserID: "u1", auth.HeaderUserRoles: "admin", auth.HeaderServiceSecret: ""},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(ResponseError())
+			app.Use(Authenticate(roles, tt.secret))
+			app.Get("/", func(c fiber.Ctx) error {
+				// handler ต้องไม่เห็น header ตัวตนที่ยังไม่ผ่านการตรวจ
+				for _, h := range []string{auth.HeaderUserID, auth.HeaderUserRoles, auth.HeaderServiceSecret} {
+					if c.Get(h) != "" {
+						t.Errorf("header %s reached the handler", h)
+					}
+				}
+				if p, ok := auth.PrincipalFromContext(c.Context()); ok {
+					return c.SendString(p.UserID)
+				}
+				return c.SendString("")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			res, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", res.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.wantUser {
+				t.Fatalf("principal = %q, want %q", body, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestRequireServiceSecret(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     string
+		sent       string
+		wantStatus int
+	}{
+		{name: "valid secret", secret: "s3cret", sent: "s3cret", wantStatus: http.StatusOK},
+		{name: "missing secret", secret: "s3cret", wantStatus: http.StatusUnauthorized},
+		{name: "wrong secret", secret: "s3cret", sent: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "secret not configured rejects everything", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(ResponseError())
+			app.Use(RequireServiceSecret(tt.secret))
+			app.Post("/internal", func(c fiber.Ctx) error {
+				return c.SendStatus(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/internal", nil)
+			if tt.sent != "" {
+				req.Header.Set(auth.HeaderServiceSecret, tt.sent)
+			}
+			res, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", res.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
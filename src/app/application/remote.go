@@ -0,0 +1,41 @@
+package application
+
+import (
+	"errors"
+	"go-mma/shared/common/mediator"
+	"go-mma/shared/contract/customercontract"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// remoteMediatorPath คือ endpoint ที่ process อื่นใช้เรียก handler ที่ลงทะเบียนด้วย mediator.Exposed
+// เปิดเฉพาะบน internal server (INTERNAL_HTTP_PORT) ซึ่งต้องมี InternalSecret ไม่ได้อยู่บน API ที่เปิดผ่าน gateway
+const remoteMediatorPath = "/internal/mediator"
+
+func (app *Application) registerRemoteRoutes() {
+	if app.internalServer == nil {
+		return
+	}
+	server := mediator.NewServer(app.mediator)
+
+	app.internalServer.Group(remoteMediatorPath).Post("/:name", func(c fiber.Ctx) error {
+		res, err := server.Handle(c.Context(), c.Params("name"), c.Body())
+		if err != nil {
+			return err
+		}
+		return c.JSON(res)
+	})
+}
+
+// UseRemoteCustomer ส่ง request ใน customercontract ไปยัง process ที่รัน customer module
+// baseURL คือที่อยู่ของ internal server ของ application นั้น เช่น http://customer:8091
+// ใช้แทนการ RegisterModules ด้วย customer.NewModule
+func (app *Application) UseRemoteCustomer(baseURL string) error {
+	t := mediator.NewHTTPTransport(baseURL+remoteMediatorPath, mediator.WithServiceSecret(app.config.InternalSecret))
+
+	return errors.Join(
+		mediator.RegisterRemote[*customercontract.GetCustomerByIDQuery, *customercontract.GetCustomerByIDQueryResult](app.mediator, t),
+		mediator.RegisterRemote[*customercontract.ReserveCreditCommand, *mediator.NoResponse](app.mediator, t),
+		mediator.RegisterRemote[*customercontract.ReleaseCreditCommand, *mediator.NoResponse](app.mediator, t),
+	)
+}
@@ -39,23 +39,33 @@ func main() {
 		}
 	}()
 
-	app := application.New(*config)
-
 	transactor, dbCtx := transactor.New(
 		db.DB(),
 		transactor.WithNestedTransactionStrategy(transactor.NestedTransactionsSavepoints),
 	)
 	mCtx := module.NewModuleContext(transactor, dbCtx)
-	err = app.RegisterModules(
-		notification.NewModule(mCtx),
-		customer.NewModule(mCtx),
-		order.NewModule(mCtx),
-	)
+
+	app, err := application.New(*config, mCtx)
+	if err != nil {
+		logger.Log.Fatal(fmt.Sprintf("Error initializing application: %v", err))
+	}
+
+	modules := []module.Module{notification.NewModule(mCtx)}
+	if config.CustomerModuleURL == "" {
+		modules = append(modules, customer.NewModule(mCtx))
+	} else if err := app.UseRemoteCustomer(config.CustomerModuleURL); err != nil {
+		logger.Log.Fatal(fmt.Sprintf("Error initializing remote customer module: %v", err))
+	}
+	modules = append(modules, order.NewModule(mCtx))
+
+	err = app.RegisterModules(modules...)
 	if err != nil {
 		logger.Log.Fatal(fmt.Sprintf("Error initializing module: %v", err))
 	}
 
-	app.Run()
+	if err := app.Run(); err != nil {
+		logger.Log.Fatal(fmt.Sprintf("Error starting application: %v", err))
+	}
 
 	// Wait for shutdown signal
 	stop := make(chan os.Signal, 1)
@@ -10,19 +10,63 @@ var (
 	ErrInvalidHTTPPort = errors.New("HTTP_PORT must be a positive integer")
 	ErrGracefulTimeout = errors.New("GRACEFUL_TIMEOUT must be a positive duration")
 	ErrDSN             = errors.New("DB_DSN must be set")
+	ErrOutboxInterval  = errors.New("OUTBOX_POLL_INTERVAL must be a positive duration")
+	ErrEventBus        = errors.New("EVENT_BUS must be one of: memory, postgres")
+	ErrEventBusWorkers = errors.New("EVENT_BUS_WORKERS must not be negative")
+	ErrBackpressure    = errors.New("EVENT_BUS_BACKPRESSURE must be one of: block, drop, error")
+	ErrEventRetention  = errors.New("EVENT_BUS_RETENTION must not be negative")
+	ErrQueryCacheSize  = errors.New("QUERY_CACHE_SIZE must be a positive integer")
+	ErrInternalPort    = errors.New("INTERNAL_HTTP_PORT must not be negative or equal to HTTP_PORT")
+	ErrInternalSecret  = errors.New("INTERNAL_SECRET must be set when INTERNAL_HTTP_PORT or CUSTOMER_MODULE_URL is set")
 )
 
 type Config struct {
 	HTTPPort        int
 	GracefulTimeout time.Duration
 	DSN             string
+	OutboxInterval  time.Duration
+	EventBus        string
+	// EventBusWorkers = 0 คือเปิด goroutine ใหม่ต่อ handler
+	EventBusWorkers      int
+	EventBusQueueSize    int
+	EventBusBackpressure string
+	// EventBusRetention คือเวลาที่เก็บ event ไว้ในตาราง event_bus_events (EVENT_BUS=postgres) ค่า 0 คือไม่ลบ
+	EventBusRetention time.Duration
+	QueryCacheSize    int
+	// GatewaySecret คือค่า X-Service-Secret ที่ API gateway ส่งมาพร้อม header ตัวตน
+	// ว่างคือไม่เชื่อ header ตัวตน ทุก request ไม่มี principal
+	GatewaySecret string
+	// InternalHTTPPort คือ port ที่รับ request จาก process อื่นผ่าน mediator ห้ามเปิดผ่าน API gateway
+	// 0 คือไม่เปิดให้ process อื่นเรียก
+	InternalHTTPPort int
+	// InternalSecret คือค่า X-Service-Secret ที่ process ภายในระบบใช้เรียกกันผ่าน InternalHTTPPort
+	InternalSecret string
+	// CustomerModuleURL ว่างคือรัน customer module ใน process นี้
+	// ถ้ากำหนด request ใน customercontract จะถูกส่งไปที่ InternalHTTPPort ของ process นั้นแทน
+	CustomerModuleURL string
 }
 
+const (
+	EventBusMemory   = "memory"
+	EventBusPostgres = "postgres"
+)
+
 func Load() (*Config, error) {
 	config := &Config{
-		HTTPPort:        env.GetIntDefault("HTTP_PORT", 8090),
-		GracefulTimeout: env.GetDurationDefault("GRACEFUL_TIMEOUT", 5*time.Second),
-		DSN:             env.Get("DB_DSN"),
+		HTTPPort:             env.GetIntDefault("HTTP_PORT", 8090),
+		GracefulTimeout:      env.GetDurationDefault("GRACEFUL_TIMEOUT", 5*time.Second),
+		DSN:                  env.Get("DB_DSN"),
+		OutboxInterval:       env.GetDurationDefault("OUTBOX_POLL_INTERVAL", time.Second),
+		EventBus:             env.GetDefault("EVENT_BUS", EventBusMemory),
+		EventBusWorkers:      env.GetIntDefault("EVENT_BUS_WORKERS", 0),
+		EventBusQueueSize:    env.GetIntDefault("EVENT_BUS_QUEUE_SIZE", 100),
+		EventBusBackpressure: env.GetDefault("EVENT_BUS_BACKPRESSURE", "block"),
+		EventBusRetention:    env.GetDurationDefault("EVENT_BUS_RETENTION", 7*24*time.Hour),
+		QueryCacheSize:       env.GetIntDefault("QUERY_CACHE_SIZE", 1000),
+		GatewaySecret:        env.Get("GATEWAY_SECRET"),
+		InternalHTTPPort:     env.GetIntDefault("INTERNAL_HTTP_PORT", 0),
+		InternalSecret:       env.Get("INTERNAL_SECRET"),
+		CustomerModuleURL:    env.Get("CUSTOMER_MODULE_URL"),
 	}
 	err := config.Validate()
 	if err != nil {
@@ -41,6 +85,32 @@ func (c *Config) Validate() error {
 	if len(c.DSN) == 0 {
 		return ErrDSN
 	}
+	if c.OutboxInterval <= 0 {
+		return ErrOutboxInterval
+	}
+	if c.EventBus != EventBusMemory && c.EventBus != EventBusPostgres {
+		return ErrEventBus
+	}
+	if c.EventBusWorkers < 0 {
+		return ErrEventBusWorkers
+	}
+	switch c.EventBusBackpressure {
+	case "block", "drop", "error":
+	default:
+		return ErrBackpressure
+	}
+	if c.EventBusRetention < 0 {
+		return ErrEventRetention
+	}
+	if c.QueryCacheSize <= 0 {
+		return ErrQueryCacheSize
+	}
+	if c.InternalHTTPPort < 0 || c.InternalHTTPPort == c.HTTPPort {
+		return ErrInternalPort
+	}
+	if (c.InternalHTTPPort > 0 || c.CustomerModuleURL != "") && c.InternalSecret == "" {
+		return ErrInternalSecret
+	}
 
 	return nil
 }
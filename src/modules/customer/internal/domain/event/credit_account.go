@@ -0,0 +1,85 @@
+package event
+
+import (
+	"go-mma/shared/common/domain"
+	"time"
+)
+
+const (
+	CreditAccountOpenedDomainEventType domain.EventName = "CreditAccountOpened"
+	CreditReservedDomainEventType      domain.EventName = "CreditReserved"
+	CreditReleasedDomainEventType      domain.EventName = "CreditReleased"
+)
+
+func init() {
+	domain.MustRegisterEventType[*CreditAccountOpenedDomainEvent](Types, CreditAccountOpenedDomainEventType)
+	domain.MustRegisterEventType[*CreditReservedDomainEvent](Types, CreditReservedDomainEventType)
+	domain.MustRegisterEventType[*CreditReleasedDomainEvent](Types, CreditReleasedDomainEventType)
+}
+
+// CreditChangedEvent คือ event ที่ทำให้ยอด credit ของลูกค้าเปลี่ยน
+type CreditChangedEvent interface {
+	domain.DomainEvent
+	CreditCustomerID() int64
+}
+
+// event เหล่านี้ถูกเก็บใน EventStore เป็น JSON ห้ามเปลี่ยนชื่อ field ของ event ที่บันทึกไปแล้ว
+
+type CreditAccountOpenedDomainEvent struct {
+	domain.BaseDomainEvent
+	CustomerID int64
+	Credit     domain.Money // event ที่บันทึกก่อนมี Money เก็บเป็นตัวเลข ซึ่ง decode เป็น DefaultCurrency
+}
+
+func NewCreditAccountOpenedDomainEvent(custID int64, credit domain.Money) *CreditAccountOpenedDomainEvent {
+	return &CreditAccountOpenedDomainEvent{
+		BaseDomainEvent: domain.BaseDomainEvent{
+			Name: CreditAccountOpenedDomainEventType,
+			At:   time.Now(),
+		},
+		CustomerID: custID,
+		Credit:     credit,
+	}
+}
+
+type CreditReservedDomainEvent struct {
+	domain.BaseDomainEvent
+	CustomerID int64
+	Amount     domain.Money
+}
+
+func NewCreditReservedDomainEvent(custID int64, amount domain.Money) *CreditReservedDomainEvent {
+	return &CreditReservedDomainEvent{
+		BaseDomainEvent: domain.BaseDomainEvent{
+			Name: CreditReservedDomainEventType,
+			At:   time.Now(),
+		},
+		CustomerID: custID,
+		Amount:     amount,
+	}
+}
+
+func (e *CreditReservedDomainEvent) CreditCustomerID() int64 {
+	return e.CustomerID
+}
+
+type CreditReleasedDomainEvent struct {
+	domain.BaseDomainEvent
+	CustomerID int64
+	Amount     domain.Money
+}
+
+func NewCreditReleasedDomainEvent(custID int64, amount domain.Money) *CreditReleasedDomainEvent {
+	return &CreditReleasedDomainEvent{
+		BaseDomainEvent: domain.BaseDomainEvent{
+			Name: CreditReleasedDomainEventType,
+			At:   time.Now(),
+		},
+		CustomerID: custID,
+		Amount:     amount,
+	}
+}
+
+func (e *CreditReleasedDomainEvent) CreditCustomerID() int64 {
+	return e.CustomerID
+}
@@ -9,6 +9,10 @@ const (
 	CustomerCreatedDomainEventType domain.EventName = "CustomerCreated"
 )
 
+func init() {
+	domain.MustRegisterEventType[*CustomerCreatedDomainEvent](Types, CustomerCreatedDomainEventType)
+}
+
 type CustomerCreatedDomainEvent struct {
 	domain.BaseDomainEvent
 	CustomerID int64
@@ -0,0 +1,7 @@
+package event
+
+import "go-mma/shared/common/domain"
+
+// Types ผูกชื่อ event ของโมดูลนี้กับ type ที่ aggregate raise (ลงทะเบียนใน init() ของไฟล์ที่ประกาศ event)
+// ใช้ทั้งตอน decode event จาก EventStore และตรวจ handler ตอนลงทะเบียนกับ dispatcher
+var Types = domain.NewEventTypes()
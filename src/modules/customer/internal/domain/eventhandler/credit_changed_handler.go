@@ -0,0 +1,26 @@
+package eventhandler
+
+import (
+	"context"
+	"go-mma/modules/customer/internal/domain/event"
+	"go-mma/shared/common/cache"
+	"go-mma/shared/common/domain"
+	"go-mma/shared/contract/customercontract"
+)
+
+type creditChangedDomainEventHandler[T event.CreditChangedEvent] struct {
+	cache cache.Cache
+}
+
+// NewCreditChangedDomainEventHandler ใช้ได้กับทุก event ที่เปลี่ยนยอด credit เช่น CreditReserved และ CreditReleased
+func NewCreditChangedDomainEventHandler[T event.CreditChangedEvent](cache cache.Cache) domain.TypedDomainEventHandler[T] {
+	return &creditChangedDomainEventHandler[T]{
+		cache: cache,
+	}
+}
+
+// Handle ลบข้อมูลลูกค้าออกจาก cache ของ GetCustomerByIDQuery (ถูกเรียกหลัง commit)
+func (h *creditChangedDomainEventHandler[T]) Handle(ctx context.Context, e T) error {
+	h.cache.Delete(customercontract.CustomerCacheKey(e.CreditCustomerID()))
+	return nil
+}
@@ -4,32 +4,27 @@ import (
 	"context"
 	"go-mma/modules/customer/internal/domain/event"
 	"go-mma/shared/common/domain"
-	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/outbox"
 	"go-mma/shared/messaging"
 )
 
 type customerCreatedDomainEventHandler struct {
-	eventBus eventbus.EventBus
+	outbox outbox.Outbox
 }
 
-func NewCustomerCreatedDomainEventHandler(eventBus eventbus.EventBus) domain.DomainEventHandler {
+func NewCustomerCreatedDomainEventHandler(outbox outbox.Outbox) domain.TypedDomainEventHandler[*event.CustomerCreatedDomainEvent] {
 	return &customerCreatedDomainEventHandler{
-		eventBus: eventBus,
+		outbox: outbox,
 	}
 }
 
-func (h *customerCreatedDomainEventHandler) Handle(ctx context.Context, evt domain.DomainEvent) error {
-	e, ok := evt.(*event.CustomerCreatedDomainEvent) // ใช้ pointer
-
-	if !ok {
-		return domain.ErrInvalidEvent
-	}
-
+func (h *customerCreatedDomainEventHandler) Handle(ctx context.Context, e *event.CustomerCreatedDomainEvent) error {
 	// สร้าง IntegrationEvent จาก Domain Event
 	integrationEvent := messaging.NewCustomerCreatedIntegrationEvent(
 		e.CustomerID,
 		e.Email,
 	)
 
-	return h.eventBus.Publish(ctx, integrationEvent)
+	// บันทึกลง outbox ใน transaction เดียวกัน แล้วให้ relay เป็นคน publish ทีหลัง
+	return h.outbox.Add(ctx, integrationEvent)
 }
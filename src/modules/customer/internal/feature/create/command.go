@@ -1,9 +1,16 @@
 package create
 
+import "go-mma/shared/common/auth"
+
 type CreateCustomerCommand struct {
 	CreateCustomerRequest // embeded type มาเพราะหน้าตาเหมือนกัน
 }
 
+// RequiredPermissions ใช้ตรวจสิทธิ์ใน mediator.Authorization
+func (c *CreateCustomerCommand) RequiredPermissions() []auth.Permission {
+	return []auth.Permission{"customer:create"}
+}
+
 type CreateCustomerCommandResult struct {
 	CreateCustomerResponse // embeded type มาเพราะหน้าตาเหมือนกัน
 }
@@ -6,76 +6,71 @@ import (
 	"go-mma/modules/customer/internal/model"
 	"go-mma/modules/customer/internal/repository"
 	"go-mma/shared/common/domain"
-	"go-mma/shared/common/logger"
-	"go-mma/shared/common/storage/sqldb/transactor"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/mediator"
 )
 
 type createCustomerCommandHandler struct {
-	transactor transactor.Transactor
 	custRepo   repository.CustomerRepository
-	dispatcher domain.DomainEventDispatcher
+	creditRepo repository.CreditAccountRepository
 }
 
 func NewCreateCustomerCommandHandler(
-	transactor transactor.Transactor,
 	custRepo repository.CustomerRepository,
-	dispatcher domain.DomainEventDispatcher,
+	creditRepo repository.CreditAccountRepository,
 ) *createCustomerCommandHandler {
 	return &createCustomerCommandHandler{
-		transactor: transactor,
 		custRepo:   custRepo,
-		dispatcher: dispatcher,
+		creditRepo: creditRepo,
 	}
 }
 
+// Handle ทำงานภายใน transaction ที่ mediator.Transaction เปิดให้
 func (h *createCustomerCommandHandler) Handle(ctx context.Context, cmd *CreateCustomerCommand) (*CreateCustomerCommandResult, error) {
+	email, err := domain.NewEmail(cmd.Email)
+	if err != nil {
+		return nil, errs.InputValidationError(err.Error())
+	}
+
 	// ตรวจสอบ business rule/invariant
-	if err := h.validateBusinessInvariant(ctx, cmd); err != nil {
+	if err := h.validateBusinessInvariant(ctx, cmd, email); err != nil {
 		return nil, err
 	}
 
 	// แปลง Command → Model
-	customer := model.NewCustomer(cmd.Email, cmd.Credit)
-
-	// ย้ายส่วนที่ติดต่อฐานข้อมูล กับส่งอีเมลมาทำงานใน WithinTransaction
-	err := h.transactor.WithinTransaction(ctx, func(ctx context.Context, registerPostCommitHook func(transactor.PostCommitHook)) error {
-
-		// ส่งไปที่ Repository Layer เพื่อบันทึกข้อมูลลงฐานข้อมูล
-		if err := h.custRepo.Create(ctx, customer); err != nil {
-			// error logging
-			logger.Log.Error(err.Error())
-			return err
-		}
-
-		// ดึง domain events จาก customer model
-		events := customer.PullDomainEvents()
-
-		// ให้ dispatch หลัง commit แล้ว
-		registerPostCommitHook(func(ctx context.Context) error {
-			return h.dispatcher.Dispatch(ctx, events)
-		})
+	customer := model.NewCustomer(email, domain.NewDefaultMoney(int64(cmd.Credit)))
 
-		return nil
-	})
+	// ส่งไปที่ Repository Layer เพื่อบันทึกข้อมูลลงฐานข้อมูล
+	if err := h.custRepo.Create(ctx, customer); err != nil {
+		return nil, err
+	}
 
+	// เปิด credit account ด้วยยอดเริ่มต้น ยอด credit หลังจากนี้เปลี่ยนผ่าน event ของ account เท่านั้น
+	account, err := model.OpenCreditAccount(customer.ID, customer.Credit)
 	if err != nil {
 		return nil, err
 	}
+	if err := h.creditRepo.Save(ctx, account); err != nil {
+		return nil, err
+	}
+
+	// domain event ของ customer ถูก dispatch ก่อน commit เพื่อให้ integration event ถูกเขียนลง outbox พร้อมกับข้อมูลลูกค้า
+	if err := mediator.TrackAggregate(ctx, customer); err != nil {
+		return nil, err
+	}
 
 	return NewCreateCustomerCommandResult(customer.ID), nil
 }
 
-func (h *createCustomerCommandHandler) validateBusinessInvariant(ctx context.Context, cmd *CreateCustomerCommand) error {
+func (h *createCustomerCommandHandler) validateBusinessInvariant(ctx context.Context, cmd *CreateCustomerCommand, email domain.Email) error {
 	// ตรวจสอบ Credit ต้องมากกว่า 0
 	if cmd.Credit <= 0 {
 		return domainerrors.ErrCreditValue
 	}
 
 	// ตรวจสอบ email ซ้ำ
-	exists, err := h.custRepo.ExistsByEmail(ctx, cmd.Email)
+	exists, err := h.custRepo.ExistsByEmail(ctx, email)
 	if err != nil {
-		// error logging
-		logger.Log.Error(err.Error())
 		return err
 	}
 
@@ -2,7 +2,7 @@ package create
 
 import (
 	"errors"
-	"net/mail"
+	"go-mma/shared/common/domain"
 )
 
 type CreateCustomerRequest struct {
@@ -12,11 +12,8 @@ type CreateCustomerRequest struct {
 
 func (r *CreateCustomerRequest) Validate() error {
 	var errs error
-	if r.Email == "" {
-		errs = errors.Join(errs, errors.New("email is required"))
-	}
-	if _, err := mail.ParseAddress(r.Email); err != nil {
-		errs = errors.Join(errs, errors.New("email is invalid"))
+	if _, err := domain.NewEmail(r.Email); err != nil {
+		errs = errors.Join(errs, err)
 	}
 	if r.Credit <= 0 {
 		errs = errors.Join(errs, errors.New("credit must be greater than 0"))
@@ -0,0 +1,45 @@
+package export
+
+import (
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/httpstream"
+	"go-mma/shared/common/mediator"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func NewEndpoint(router fiber.Router, path string, med *mediator.Mediator) {
+	router.Get(path, exportCustomersHTTPHandler(med))
+}
+
+var csvHeader = []string{"id", "email", "credit", "created_at"}
+
+func exportCustomersHTTPHandler(med *mediator.Mediator) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		// 1. เลือกรูปแบบไฟล์ (ndjson เป็นค่าเริ่มต้น)
+		format := fiber.Query(c, "format", "ndjson")
+		if format != "ndjson" && format != "csv" {
+			return errs.InputValidationError("format must be one of: ndjson, csv")
+		}
+
+		// 2. ส่งไปที่ Stream Handler ผลลัพธ์จะถูกอ่านทีละรายการตอนเขียน response
+		items := mediator.StreamRequest[*ExportCustomersQuery, *ExportCustomerItem](c.Context(), med, &ExportCustomersQuery{})
+
+		// 3. ตอบกลับ client
+		if format == "csv" {
+			return httpstream.CSV(c, "customers.csv", csvHeader, toCSVRow, items)
+		}
+		return httpstream.NDJSON(c, items)
+	}
+}
+
+func toCSVRow(item *ExportCustomerItem) []string {
+	return []string{
+		strconv.FormatInt(item.ID, 10),
+		item.Email,
+		strconv.Itoa(item.Credit),
+		item.CreatedAt.Format(time.RFC3339),
+	}
+}
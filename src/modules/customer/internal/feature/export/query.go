@@ -0,0 +1,20 @@
+package export
+
+import (
+	"go-mma/shared/common/auth"
+	"time"
+)
+
+type ExportCustomersQuery struct{}
+
+// RequiredPermissions ใช้ตรวจสิทธิ์ใน mediator.Authorization
+func (q *ExportCustomersQuery) RequiredPermissions() []auth.Permission {
+	return []auth.Permission{"customer:export"}
+}
+
+type ExportCustomerItem struct {
+	ID        int64     `json:"id"`
+	Email     string    `json:"email"`
+	Credit    int       `json:"credit"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,37 @@
+package export
+
+import (
+	"context"
+	"go-mma/modules/customer/internal/repository"
+	"iter"
+)
+
+type exportCustomersQueryHandler struct {
+	custRepo repository.CustomerRepository
+}
+
+func NewExportCustomersQueryHandler(custRepo repository.CustomerRepository) *exportCustomersQueryHandler {
+	return &exportCustomersQueryHandler{
+		custRepo: custRepo,
+	}
+}
+
+func (h *exportCustomersQueryHandler) Handle(ctx context.Context, query *ExportCustomersQuery) iter.Seq2[*ExportCustomerItem, error] {
+	return func(yield func(*ExportCustomerItem, error) bool) {
+		for customer, err := range h.custRepo.All(ctx) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			item := &ExportCustomerItem{
+				ID:        customer.ID,
+				Email:     customer.Email.String(),
+				Credit:    int(customer.Credit.Amount()),
+				CreatedAt: customer.CreatedAt,
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
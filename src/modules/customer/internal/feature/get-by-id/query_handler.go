@@ -32,7 +32,7 @@ func (h *getCustomerByIDQueryHandler) Handle(ctx context.Context, query *custome
 func (h *getCustomerByIDQueryHandler) newGetCustomerByIDQueryResult(customer *model.Customer) *customercontract.GetCustomerByIDQueryResult {
 	return &customercontract.GetCustomerByIDQueryResult{
 		ID:     customer.ID,
-		Email:  customer.Email,
-		Credit: customer.Credit,
+		Email:  customer.Email.String(),
+		Credit: int(customer.Credit.Amount()),
 	}
 }
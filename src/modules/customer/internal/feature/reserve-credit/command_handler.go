@@ -4,50 +4,55 @@ import (
 	"context"
 	"go-mma/modules/customer/domainerrors"
 	"go-mma/modules/customer/internal/repository"
-	"go-mma/shared/common/errs"
-	"go-mma/shared/common/logger"
+	"go-mma/shared/common/domain"
 	"go-mma/shared/common/mediator"
-	"go-mma/shared/common/storage/sqldb/transactor"
 	"go-mma/shared/contract/customercontract"
 )
 
 type reserveCreditCommandHandler struct {
-	transactor transactor.Transactor
 	custRepo   repository.CustomerRepository
+	creditRepo repository.CreditAccountRepository
 }
 
 func NewReserveCreditCommandHandler(
-	transactor transactor.Transactor,
-	repo repository.CustomerRepository) *reserveCreditCommandHandler {
+	custRepo repository.CustomerRepository,
+	creditRepo repository.CreditAccountRepository,
+) *reserveCreditCommandHandler {
 	return &reserveCreditCommandHandler{
-		transactor: transactor,
-		custRepo:   repo,
+		custRepo:   custRepo,
+		creditRepo: creditRepo,
 	}
 }
 
+// Handle ทำงานภายใน transaction ที่ mediator.Transaction เปิดให้
 func (h *reserveCreditCommandHandler) Handle(ctx context.Context, cmd *customercontract.ReserveCreditCommand) (*mediator.NoResponse, error) {
-	err := h.transactor.WithinTransaction(ctx, func(ctx context.Context, registerPostCommitHook func(transactor.PostCommitHook)) error {
-		customer, err := h.custRepo.FindByID(ctx, cmd.CustomerID)
-		if err != nil {
-			logger.Log.Error(err.Error())
-			return err
-		}
-
-		if customer == nil {
-			return domainerrors.ErrCustomerNotFound
-		}
-
-		if err := customer.ReserveCredit(cmd.CreditAmount); err != nil {
-			return err
-		}
-
-		if err := h.custRepo.UpdateCredit(ctx, customer); err != nil {
-			logger.Log.Error(err.Error())
-			return errs.DatabaseFailureError(err.Error())
-		}
-
-		return nil
-	})
-
-	return nil, err
+	account, err := h.creditRepo.FindByCustomerID(ctx, cmd.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account == nil {
+		return nil, domainerrors.ErrCustomerNotFound
+	}
+
+	if err := account.Reserve(domain.NewDefaultMoney(int64(cmd.CreditAmount))); err != nil {
+		return nil, err
+	}
+
+	// domain event ของ credit account จะถูก dispatch หลัง commit
+	if err := mediator.TrackAggregate(ctx, account); err != nil {
+		return nil, err
+	}
+
+	// ถ้ามี request อื่นบันทึก event ของลูกค้าคนเดียวกันไปก่อน จะได้ concurrency conflict และ mediator.RetryOnConflict ลองใหม่
+	if err := h.creditRepo.Save(ctx, account); err != nil {
+		return nil, err
+	}
+
+	// อัปเดตยอดใน customers ให้ query อื่นอ่านได้ใน transaction เดียวกัน
+	if err := h.custRepo.UpdateCredit(ctx, account.CustomerID, account.Balance); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
 }
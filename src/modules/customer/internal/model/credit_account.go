@@ -0,0 +1,113 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-mma/modules/customer/domainerrors"
+	"go-mma/modules/customer/internal/domain/event"
+	"go-mma/shared/common/domain"
+)
+
+// CreditAccount คือยอด credit ของลูกค้าแบบ event-sourced
+// ทุกการจองและคืน credit ถูกเก็บเป็น event และยอดคงเหลือได้จากการ apply event ตามลำดับ
+type CreditAccount struct {
+	CustomerID int64
+	Balance    domain.Money
+	domain.EventSourcedAggregate
+}
+
+// CreditAccountStreamID คือชื่อ stream ใน EventStore ของลูกค้าแต่ละคน
+func CreditAccountStreamID(customerID int64) string {
+	return fmt.Sprintf("customer-credit-%d", customerID)
+}
+
+// NewEmptyCreditAccount ใช้ให้ repository apply event ลงไป
+func NewEmptyCreditAccount() *CreditAccount {
+	return &CreditAccount{}
+}
+
+func OpenCreditAccount(customerID int64, credit domain.Money) (*CreditAccount, error) {
+	if !credit.IsPositive() {
+		return nil, domainerrors.ErrCreditValue
+	}
+
+	a := &CreditAccount{}
+	if err := a.Raise(a, event.NewCreditAccountOpenedDomainEvent(customerID, credit)); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// OpenCreditAccountFromBalance เปิด credit account จากยอดที่มีอยู่แล้วใน customers.credit
+// ใช้กับลูกค้าที่ยังไม่มี stream ยอด 0 จึงเปิดได้ ต่างจาก OpenCreditAccount ที่ใช้ตอนสร้างลูกค้าใหม่
+func OpenCreditAccountFromBalance(customerID int64, balance domain.Money) (*CreditAccount, error) {
+	if balance.IsNegative() {
+		return nil, domainerrors.ErrCreditValue
+	}
+
+	a := &CreditAccount{}
+	if err := a.Raise(a, event.NewCreditAccountOpenedDomainEvent(customerID, balance)); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reserve และ Release รับเฉพาะยอดที่มากกว่า 0 เพราะ event ที่บันทึกแล้วแก้ไม่ได้
+func (a *CreditAccount) Reserve(amount domain.Money) error {
+	if !amount.IsPositive() {
+		return domainerrors.ErrCreditValue
+	}
+	balance, err := a.Balance.Sub(amount)
+	if err != nil {
+		return err
+	}
+	if balance.IsNegative() {
+		return domainerrors.ErrInsufficientCredit
+	}
+	return a.Raise(a, event.NewCreditReservedDomainEvent(a.CustomerID, amount))
+}
+
+func (a *CreditAccount) Release(amount domain.Money) error {
+	if !amount.IsPositive() {
+		return domainerrors.ErrCreditValue
+	}
+	return a.Raise(a, event.NewCreditReleasedDomainEvent(a.CustomerID, amount))
+}
+
+func (a *CreditAccount) StreamID() string {
+	return CreditAccountStreamID(a.CustomerID)
+}
+
+func (a *CreditAccount) Apply(e domain.DomainEvent) (err error) {
+	switch e := e.(type) {
+	case *event.CreditAccountOpenedDomainEvent:
+		a.CustomerID = e.CustomerID
+		a.Balance = e.Credit
+	case *event.CreditReservedDomainEvent:
+		a.Balance, err = a.Balance.Sub(e.Amount)
+	case *event.CreditReleasedDomainEvent:
+		a.Balance, err = a.Balance.Add(e.Amount)
+	default:
+		err = fmt.Errorf("%w: %s", domain.ErrUnknownEventType, e.EventName())
+	}
+	return err
+}
+
+type creditAccountSnapshot struct {
+	CustomerID int64        `json:"customer_id"`
+	Balance    domain.Money `json:"balance"`
+}
+
+func (a *CreditAccount) Snapshot() ([]byte, error) {
+	return json.Marshal(creditAccountSnapshot{CustomerID: a.CustomerID, Balance: a.Balance})
+}
+
+func (a *CreditAccount) RestoreSnapshot(state []byte) error {
+	var s creditAccountSnapshot
+	if err := json.Unmarshal(state, &s); err != nil {
+		return err
+	}
+	a.CustomerID = s.CustomerID
+	a.Balance = s.Balance
+	return nil
+}
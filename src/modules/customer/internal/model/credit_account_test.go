@@ -0,0 +1,59 @@
+package model
+
+import (
+	"errors"
+	"go-mma/modules/customer/domainerrors"
+	"go-mma/modules/customer/internal/domain/event"
+	"go-mma/shared/common/domain"
+	"testing"
+)
+
+func TestCreditAccountRejectsNonPositiveAmount(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(a *CreditAccount, amount domain.Money) error
+		amount int64
+	}{
+		{name: "reserve zero", change: (*CreditAccount).Reserve, amount: 0},
+		{name: "reserve negative", change: (*CreditAccount).Reserve, amount: -1000},
+		{name: "release zero", change: (*CreditAccount).Release, amount: 0},
+		{name: "release negative", change: (*CreditAccount).Release, amount: -1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := OpenCreditAccount(1, domain.NewDefaultMoney(500))
+			if err != nil {
+				t.Fatal(err)
+			}
+			a.PullDomainEvents()
+
+			if err := tt.change(a, domain.NewDefaultMoney(tt.amount)); !errors.Is(err, domainerrors.ErrCreditValue) {
+				t.Fatalf("error = %v, want %v", err, domainerrors.ErrCreditValue)
+			}
+			if a.Balance.Amount() != 500 {
+				t.Fatalf("balance = %d, want 500", a.Balance.Amount())
+			}
+			if events := a.PullDomainEvents(); len(events) != 0 {
+				t.Fatalf("raised %d event(s), want none", len(events))
+			}
+		})
+	}
+}
+
+// replay ต้องได้ยอดตาม event ทุกตัว ไม่แก้ยอดให้เอง
+func TestCreditAccountReplaysReleaseAsRecorded(t *testing.T) {
+	a := NewEmptyCreditAccount()
+	for _, e := range []domain.DomainEvent{
+		event.NewCreditAccountOpenedDomainEvent(1, domain.NewDefaultMoney(0)),
+		event.NewCreditReservedDomainEvent(1, domain.NewDefaultMoney(100)),
+		event.NewCreditReleasedDomainEvent(1, domain.NewDefaultMoney(30)),
+	} {
+		if err := a.Apply(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.Balance.Amount() != -70 {
+		t.Fatalf("balance = %d, want -70", a.Balance.Amount())
+	}
+}
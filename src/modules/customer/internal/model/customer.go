@@ -1,7 +1,6 @@
 package model
 
 import (
-	"go-mma/modules/customer/domainerrors"
 	"go-mma/modules/customer/internal/domain/event"
 	"go-mma/shared/common/domain"
 	"go-mma/shared/common/idgen"
@@ -9,39 +8,22 @@ import (
 )
 
 type Customer struct {
-	ID               int64     `db:"id"`
-	Email            string    `db:"email"`
-	Credit           int       `db:"credit"`
-	CreatedAt        time.Time `db:"created_at"`
-	UpdatedAt        time.Time `db:"updated_at"`
-	domain.Aggregate           // ทำให้ model เป็น aggregate root มี domain events
+	ID               int64        `db:"id"`
+	Email            domain.Email `db:"email"`
+	Credit           domain.Money `db:"credit"` // ยอดล่าสุดของ CreditAccount ใช้สำหรับอ่าน
+	CreatedAt        time.Time    `db:"created_at"`
+	UpdatedAt        time.Time    `db:"updated_at"`
+	domain.Aggregate              // ทำให้ model เป็น aggregate root มี domain events
 }
 
-func NewCustomer(email string, credit int) *Customer {
+func NewCustomer(email domain.Email, credit domain.Money) *Customer {
 	customer := &Customer{
 		ID:     idgen.GenerateTimeRandomID(),
 		Email:  email,
 		Credit: credit,
 	}
 
-	customer.AddDomainEvent(event.NewCustomerCreatedDomainEvent(customer.ID, customer.Email))
+	customer.AddDomainEvent(event.NewCustomerCreatedDomainEvent(customer.ID, customer.Email.String()))
 
 	return customer
 }
-
-func (c *Customer) ReserveCredit(v int) error {
-	newCredit := c.Credit - v
-	if newCredit < 0 {
-		return domainerrors.ErrInsufficientCredit
-	}
-	c.Credit = newCredit
-	return nil
-}
-
-func (c *Customer) ReleaseCredit(v int) {
-	if c.Credit <= 0 {
-		c.Credit = 0
-	}
-	c.Credit = c.Credit + v
-	return
-}
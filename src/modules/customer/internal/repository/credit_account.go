@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-mma/modules/customer/internal/domain/event"
+	"go-mma/modules/customer/internal/model"
+	"go-mma/shared/common/domain"
+)
+
+// snapshot ทุก 50 event ทำให้โหลด credit account ไม่ต้องอ่าน event มากกว่านี้
+const creditAccountSnapshotEvery = 50
+
+type CreditAccountRepository interface {
+	// FindByCustomerID คืน nil ถ้าไม่มีลูกค้าคนนี้
+	// ลูกค้าที่ยังไม่มี stream (เช่นสร้างโดยโค้ดเวอร์ชันก่อนระหว่าง rolling deploy) จะได้ account ใหม่ที่เปิดจาก customers.credit
+	// ซึ่งถูกบันทึกเป็น stream เมื่อ Save
+	FindByCustomerID(ctx context.Context, customerID int64) (*model.CreditAccount, error)
+	// Save คืน errs.ErrConcurrency ถ้ามี event ใหม่ใน stream หลังจากที่โหลดมา
+	Save(ctx context.Context, account *model.CreditAccount) error
+}
+
+type creditAccountRepository struct {
+	repo      *domain.EventSourcedRepository[*model.CreditAccount]
+	customers CustomerRepository
+}
+
+func NewCreditAccountRepository(store domain.EventStore, customers CustomerRepository) CreditAccountRepository {
+	return &creditAccountRepository{
+		repo: domain.NewEventSourcedRepository(store, event.Types, model.NewEmptyCreditAccount,
+			domain.WithSnapshotEvery(creditAccountSnapshotEvery),
+		),
+		customers: customers,
+	}
+}
+
+func (r *creditAccountRepository) FindByCustomerID(ctx context.Context, customerID int64) (*model.CreditAccount, error) {
+	account, err := r.repo.Load(ctx, model.CreditAccountStreamID(customerID))
+	if err != nil {
+		if errors.Is(err, domain.ErrStreamNotFound) {
+			return r.openFromCustomer(ctx, customerID)
+		}
+		return nil, err
+	}
+	return account, nil
+}
+
+// openFromCustomer เปิด account จากยอดใน customers.credit แบบเดียวกับ migration ที่ backfill stream
+// ถ้า request อื่นเปิด stream ไปก่อน Save จะคืน errs.ErrConcurrency และ mediator.RetryOnConflict โหลดใหม่จาก stream
+func (r *creditAccountRepository) openFromCustomer(ctx context.Context, customerID int64) (*model.CreditAccount, error) {
+	customer, err := r.customers.FindByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, nil
+	}
+	return model.OpenCreditAccountFromBalance(customer.ID, customer.Credit)
+}
+
+func (r *creditAccountRepository) Save(ctx context.Context, account *model.CreditAccount) error {
+	return r.repo.Save(ctx, account)
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"go-mma/modules/customer/internal/model"
+	"go-mma/shared/common/domain"
+	"go-mma/shared/common/errs"
+	"testing"
+)
+
+// memoryEventStore เก็บ event ไว้ในหน่วยความจำ ตรวจ expectedVersion แบบเดียวกับ store จริง
+type memoryEventStore struct {
+	streams map[string][]domain.RecordedEvent
+}
+
+func newMemoryEventStore() *memoryEventStore {
+	return &memoryEventStore{streams: make(map[string][]domain.RecordedEvent)}
+}
+
+func (s *memoryEventStore) Append(ctx context.Context, streamID string, expectedVersion int, events []domain.RecordedEvent) error {
+	if current := len(s.streams[streamID]); current != expectedVersion {
+		return errs.ConcurrencyConflictError("stream " + streamID + " was modified")
+	}
+	s.streams[streamID] = append(s.streams[streamID], events...)
+	return nil
+}
+
+func (s *memoryEventStore) Load(ctx context.Context, streamID string, afterVersion int) ([]domain.RecordedEvent, error) {
+	events := s.streams[streamID]
+	if afterVersion >= len(events) {
+		return nil, nil
+	}
+	return events[afterVersion:], nil
+}
+
+func (s *memoryEventStore) LoadSnapshot(ctx context.Context, streamID string) (*domain.Snapshot, error) {
+	return nil, nil
+}
+
+func (s *memoryEventStore) SaveSnapshot(ctx context.Context, snapshot *domain.Snapshot) error {
+	return nil
+}
+
+// customerRows แทนตาราง customers ใช้เฉพาะ FindByID
+type customerRows struct {
+	CustomerRepository
+	rows map[int64]*model.Customer
+}
+
+func (r *customerRows) FindByID(ctx context.Context, id int64) (*model.Customer, error) {
+	return r.rows[id], nil
+}
+
+func TestFindByCustomerIDOpensMissingStreamFromCustomerCredit(t *testing.T) {
+	tests := []struct {
+		name        string
+		customer    *model.Customer
+		wantAccount bool
+		wantBalance int64
+	}{
+		{
+			name:        "customer with credit",
+			customer:    &model.Customer{ID: 1, Credit: domain.NewDefaultMoney(300)},
+			wantAccount: true,
+			wantBalance: 300,
+		},
+		{
+			name:        "customer with zero credit",
+			customer:    &model.Customer{ID: 1, Credit: domain.NewDefaultMoney(0)},
+			wantAccount: true,
+			wantBalance: 0,
+		},
+		{
+			name: "customer not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customers := &customerRows{rows: map[int64]*model.Customer{}}
+			if tt.customer != nil {
+				customers.rows[tt.customer.ID] = tt.customer
+			}
+			repo := NewCreditAccountRepository(newMemoryEventStore(), customers)
+
+			account, err := repo.FindByCustomerID(context.Background(), 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (account != nil) != tt.wantAccount {
+				t.Fatalf("account = %v, want account: %v", account, tt.wantAccount)
+			}
+			if account == nil {
+				return
+			}
+			if account.CustomerID != 1 || account.Balance.Amount() != tt.wantBalance {
+				t.Fatalf("account = {customer %d, balance %d}, want {customer 1, balance %d}", account.CustomerID, account.Balance.Amount(), tt.wantBalance)
+			}
+		})
+	}
+}
+
+func TestOpenedAccountIsSavedAsStream(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryEventStore()
+	customers := &customerRows{rows: map[int64]*model.Customer{
+		1: {ID: 1, Credit: domain.NewDefaultMoney(300)},
+	}}
+	repo := NewCreditAccountRepository(store, customers)
+
+	account, err := repo.FindByCustomerID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := account.Reserve(domain.NewDefaultMoney(100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatal(err)
+	}
+
+	// หลังจากนี้ยอดมาจาก stream ไม่ใช่ customers.credit
+	customers.rows[1].Credit = domain.NewDefaultMoney(999)
+	loaded, err := repo.FindByCustomerID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Balance.Amount() != 200 {
+		t.Fatalf("balance = %d, want 200 from the stream", loaded.Balance.Amount())
+	}
+	if got := len(store.streams[model.CreditAccountStreamID(1)]); got != 2 {
+		t.Fatalf("stream has %d event(s), want opened and reserved", got)
+	}
+}
+
+// request สองตัวที่เปิด stream จาก customers.credit พร้อมกัน ตัวที่ save ทีหลังต้องได้ ErrConcurrency เพื่อให้ลองใหม่
+func TestConcurrentOpenConflicts(t *testing.T) {
+	ctx := context.Background()
+	customers := &customerRows{rows: map[int64]*model.Customer{
+		1: {ID: 1, Credit: domain.NewDefaultMoney(300)},
+	}}
+	repo := NewCreditAccountRepository(newMemoryEventStore(), customers)
+
+	first, err := repo.FindByCustomerID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := repo.FindByCustomerID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Save(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(ctx, second); errs.GetErrorType(err) != errs.ErrConcurrency {
+		t.Fatalf("error = %v, want %v", err, errs.ErrConcurrency)
+	}
+}
@@ -2,19 +2,27 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"go-mma/modules/customer/internal/model"
+	"go-mma/shared/common/domain"
 	"go-mma/shared/common/errs"
+	"go-mma/shared/common/storage/sqldb/criteria"
 	"go-mma/shared/common/storage/sqldb/transactor"
+	"iter"
 	"time"
 )
 
 type CustomerRepository interface {
 	Create(ctx context.Context, customer *model.Customer) error
-	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	ExistsByEmail(ctx context.Context, email domain.Email) (bool, error)
 	FindByID(ctx context.Context, id int64) (*model.Customer, error)
-	UpdateCredit(ctx context.Context, customer *model.Customer) error
+	// Find คืนลูกค้าที่ตรงกับ c เช่น criteria.Where(CustomerByEmail(email))
+	Find(ctx context.Context, c *criteria.Criteria) ([]*model.Customer, error)
+	// UpdateCredit เก็บยอดล่าสุดของ CreditAccount ไว้ให้อ่าน ต้องเรียกใน transaction เดียวกับที่บันทึก event
+	// ไม่ตรวจ customers.version เพราะ stream ของ CreditAccount ใน event store เป็นตัวกันการแก้ credit พร้อมกันเพียงตัวเดียว
+	UpdateCredit(ctx context.Context, id int64, credit domain.Money) error
+	// All อ่านลูกค้าทั้งหมดทีละแถวเรียงตาม id โดยไม่โหลดทั้งหมดไว้ในหน่วยความจำ
+	All(ctx context.Context) iter.Seq2[*model.Customer, error]
 }
 
 type customerRepository struct {
@@ -47,64 +55,85 @@ RETURNING *
 	return nil // Return nil if the operation is successful
 }
 
-func (r *customerRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
-	query := `
-	SELECT 1
-	FROM public.customers
-	WHERE email = $1
-	LIMIT 1
-	`
-
+func (r *customerRepository) ExistsByEmail(ctx context.Context, email domain.Email) (bool, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	var exists int
-	err := r.dbCtx(ctx).
-		QueryRowxContext(ctx, query, email).
-		Scan(&exists)
+	exists, err := criteria.Exists(ctx, r.dbCtx, "public.customers", criteria.Where(CustomerByEmail(email)))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return false, nil
-		}
 		return false, errs.HandleDBError(fmt.Errorf("failed to select customer: %w", err))
 	}
-	return true, nil
+	return exists, nil
 }
 
 func (r *customerRepository) FindByID(ctx context.Context, id int64) (*model.Customer, error) {
-	query := `
-	SELECT *
-	FROM public.customers
-	WHERE id = $1
-`
 	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 	defer cancel()
 
-	var customer model.Customer
-	err := r.dbCtx(ctx).QueryRowxContext(ctx, query, id).StructScan(&customer)
+	customer, err := criteria.First[model.Customer](ctx, r.dbCtx, "public.customers", criteria.Where(CustomerByID(id)))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
 		return nil, errs.HandleDBError(fmt.Errorf("failed to get customer by ID: %w", err))
 	}
+	return customer, nil
+}
+
+func (r *customerRepository) Find(ctx context.Context, c *criteria.Criteria) ([]*model.Customer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
 
-	return &customer, nil
+	customers, err := criteria.Select[model.Customer](ctx, r.dbCtx, "public.customers", c)
+	if err != nil {
+		return nil, errs.HandleDBError(fmt.Errorf("failed to find customers: %w", err))
+	}
+	return customers, nil
 }
 
-func (r *customerRepository) UpdateCredit(ctx context.Context, m *model.Customer) error {
+func (r *customerRepository) UpdateCredit(ctx context.Context, id int64, credit domain.Money) error {
 	query := `
 	UPDATE public.customers
-	SET credit = $2
+	SET credit = $2,
+		updated_at = current_timestamp
 	WHERE id = $1
-	RETURNING *
 `
 	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 	defer cancel()
 
-	err := r.dbCtx(ctx).QueryRowxContext(ctx, query, m.ID, m.Credit).StructScan(m)
-	if err != nil {
+	if _, err := r.dbCtx(ctx).ExecContext(ctx, query, id, credit); err != nil {
 		return errs.HandleDBError(fmt.Errorf("failed to update customer credit: %w", err))
 	}
 	return nil
 }
+
+func (r *customerRepository) All(ctx context.Context) iter.Seq2[*model.Customer, error] {
+	return func(yield func(*model.Customer, error) bool) {
+		query := `
+	SELECT *
+	FROM public.customers
+	ORDER BY id
+`
+		// connection ถูกใช้อยู่จนกว่าจะอ่านครบ จึงให้เวลานานกว่า query ปกติ
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		rows, err := r.dbCtx(ctx).QueryxContext(ctx, query)
+		if err != nil {
+			yield(nil, errs.HandleDBError(fmt.Errorf("failed to list customers: %w", err)))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var customer model.Customer
+			if err := rows.StructScan(&customer); err != nil {
+				yield(nil, errs.HandleDBError(fmt.Errorf("failed to scan customer: %w", err)))
+				return
+			}
+			if !yield(&customer, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(nil, errs.HandleDBError(fmt.Errorf("failed to list customers: %w", err)))
+		}
+	}
+}
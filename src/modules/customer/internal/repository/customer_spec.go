@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"go-mma/shared/common/domain"
+	"go-mma/shared/common/storage/sqldb/criteria"
+)
+
+// เงื่อนไขของลูกค้าสำหรับ CustomerRepository.Find ประกอบกันด้วย criteria.Where, criteria.And และ criteria.Or
+
+func CustomerByID(id int64) criteria.Predicate {
+	return criteria.Eq("id", id)
+}
+
+// CustomerByEmail เทียบแบบไม่สนตัวพิมพ์ เพราะข้อมูลเก่าอาจถูกบันทึกก่อนที่ Email จะ normalize เป็นตัวพิมพ์เล็ก
+func CustomerByEmail(email domain.Email) criteria.Predicate {
+	return criteria.EqFold("email", email)
+}
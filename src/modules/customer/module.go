@@ -1,9 +1,11 @@
 package customer
 
 import (
+	"errors"
 	"go-mma/modules/customer/internal/domain/event"
 	"go-mma/modules/customer/internal/domain/eventhandler"
 	"go-mma/modules/customer/internal/feature/create"
+	"go-mma/modules/customer/internal/feature/export"
 	getbyid "go-mma/modules/customer/internal/feature/get-by-id"
 	releasecredit "go-mma/modules/customer/internal/feature/release-credit"
 	reservecredit "go-mma/modules/customer/internal/feature/reserve-credit"
@@ -31,20 +33,42 @@ func (m *moduleImp) APIVersion() string {
 
 func (m *moduleImp) Init(reg registry.ServiceRegistry, eventBus eventbus.EventBus) error {
 	// Register domain event handlerAdd commentMore actions
-	dispatcher := domain.NewSimpleDomainEventDispatcher()
-	dispatcher.Register(event.CustomerCreatedDomainEventType, eventhandler.NewCustomerCreatedDomainEventHandler(eventBus))
+	// handler ต้องรับ type เดียวกับที่ประกาศไว้ใน event.Types ไม่เช่นนั้นจะผิดพลาดตั้งแต่ตรงนี้
+	dispatcher := domain.NewSimpleDomainEventDispatcher(domain.WithEventTypes(event.Types))
+	// integration event ต้องลง outbox ใน transaction เดียวกับข้อมูลลูกค้า
+	if err := domain.RegisterTyped(dispatcher, event.CustomerCreatedDomainEventType, eventhandler.NewCustomerCreatedDomainEventHandler(m.mCtx.Outbox), domain.InTransaction()); err != nil {
+		return err
+	}
+	if err := domain.RegisterTyped(dispatcher, event.CreditReservedDomainEventType, eventhandler.NewCreditChangedDomainEventHandler[*event.CreditReservedDomainEvent](m.mCtx.QueryCache)); err != nil {
+		return err
+	}
+	if err := domain.RegisterTyped(dispatcher, event.CreditReleasedDomainEventType, eventhandler.NewCreditChangedDomainEventHandler[*event.CreditReleasedDomainEvent](m.mCtx.QueryCache)); err != nil {
+		return err
+	}
 
 	repo := repository.NewCustomerRepository(m.mCtx.DBCtx)
+	creditRepo := repository.NewCreditAccountRepository(m.mCtx.EventStore, repo)
 
-	mediator.Register(create.NewCreateCustomerCommandHandler(m.mCtx.Transactor, repo, dispatcher))
-	mediator.Register(getbyid.NewGetCustomerByIDQueryHandler(repo))
-	mediator.Register(reservecredit.NewReserveCreditCommandHandler(m.mCtx.Transactor, repo))
-	mediator.Register(releasecredit.NewReleaseCreditCommandHandler(m.mCtx.Transactor, repo))
+	// command ที่แก้ไขข้อมูลทำงานภายใน transaction ที่ mediator เปิดให้
+	// domain event ของ aggregate ที่ถูก TrackAggregate จะถูก dispatch ทั้งก่อน commit (handler แบบ InTransaction) และหลัง commit
+	txOpts := []mediator.TransactionOption{mediator.DispatchBeforeCommit(dispatcher), mediator.DispatchAfterCommit(dispatcher)}
+	tx := mediator.WithBehaviors(mediator.Transaction(m.mCtx.Transactor, txOpts...))
+	// command ที่แก้ยอด credit อาจชนกับ request อื่นที่แก้ customer คนเดียวกัน จึงลองใหม่ด้วยข้อมูลล่าสุด
+	txRetry := mediator.WithBehaviors(mediator.RetryOnConflict(3), mediator.Transaction(m.mCtx.Transactor, txOpts...))
+	// request ใน customercontract เปิดให้ process อื่นเรียกได้ เผื่อโมดูลที่เรียกใช้ถูกแยกออกไป
+	exposed := mediator.Exposed()
 
-	return nil
+	return errors.Join(
+		mediator.RegisterHandler(m.mCtx.Mediator, create.NewCreateCustomerCommandHandler(repo, creditRepo), tx),
+		mediator.RegisterHandler(m.mCtx.Mediator, getbyid.NewGetCustomerByIDQueryHandler(repo), exposed),
+		mediator.RegisterHandler(m.mCtx.Mediator, reservecredit.NewReserveCreditCommandHandler(repo, creditRepo), txRetry, exposed),
+		mediator.RegisterHandler(m.mCtx.Mediator, releasecredit.NewReleaseCreditCommandHandler(repo, creditRepo), txRetry, exposed),
+		mediator.RegisterStreamHandler(m.mCtx.Mediator, export.NewExportCustomersQueryHandler(repo)),
+	)
 }
 
 func (m *moduleImp) RegisterRoutes(router fiber.Router) {
 	customers := router.Group("/customers")
-	create.NewEndpoint(customers, "")
+	create.NewEndpoint(customers, "", m.mCtx.Mediator)
+	export.NewEndpoint(customers, "/export", m.mCtx.Mediator)
 }
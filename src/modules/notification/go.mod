@@ -6,9 +6,15 @@ replace go-mma/shared/common v0.0.0 => ../../shared/common
 
 replace go-mma/shared/messaging v0.0.0 => ../../shared/messaging
 
+replace go-mma/shared/contract/customercontract v0.0.0 => ../../shared/contract/customer-contract
+
+replace go-mma/shared/contract/ordercontract v0.0.0 => ../../shared/contract/order-contract
+
 require (
 	github.com/gofiber/fiber/v3 v3.0.0-beta.4
 	go-mma/shared/common v0.0.0
+	go-mma/shared/contract/customercontract v0.0.0
+	go-mma/shared/contract/ordercontract v0.0.0
 	go-mma/shared/messaging v0.0.0
 )
 
@@ -20,6 +26,7 @@ require (
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/jmoiron/sqlx v1.4.0 // indirect
 	github.com/klauspost/compress v1.17.11 // indirect
+	github.com/lib/pq v1.10.9 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/philhofer/fwd v1.1.3-0.20240916144458-20a13a1f6b7c // indirect
@@ -2,9 +2,7 @@ package customer
 
 import (
 	"context"
-	"fmt"
 	"go-mma/modules/notification/service"
-	"go-mma/shared/common/eventbus"
 	"go-mma/shared/messaging"
 )
 
@@ -18,12 +16,7 @@ func NewWelcomeEmailHandler(notiService service.NotificationService) *welcomeEma
 	}
 }
 
-func (h *welcomeEmailHandler) Handle(ctx context.Context, evt eventbus.Event) error {
-	e, ok := evt.(messaging.CustomerCreatedIntegrationEvent)
-	if !ok {
-		return fmt.Errorf("invalid event type")
-	}
-
+func (h *welcomeEmailHandler) Handle(ctx context.Context, e *messaging.CustomerCreatedIntegrationEvent) error {
 	return h.notiService.SendEmail(e.Email, "Welcome to our service!", map[string]any{
 		"message": "Thank you for joining us! We are excited to have you as a member.",
 	})
@@ -0,0 +1,38 @@
+package order
+
+import (
+	"context"
+	"go-mma/modules/notification/service"
+	"go-mma/shared/common/mediator"
+	"go-mma/shared/contract/customercontract"
+	"go-mma/shared/contract/ordercontract"
+)
+
+type orderCanceledEmailHandler struct {
+	mediator    *mediator.Mediator
+	notiService service.NotificationService
+}
+
+func NewOrderCanceledEmailHandler(mediator *mediator.Mediator, notiService service.NotificationService) *orderCanceledEmailHandler {
+	return &orderCanceledEmailHandler{
+		mediator:    mediator,
+		notiService: notiService,
+	}
+}
+
+func (h *orderCanceledEmailHandler) Handle(ctx context.Context, n *ordercontract.OrderCanceledNotification) error {
+	// หาอีเมลของลูกค้าจากโมดูล customer
+	customer, err := mediator.SendRequest[*customercontract.GetCustomerByIDQuery, *customercontract.GetCustomerByIDQueryResult](
+		ctx,
+		h.mediator,
+		&customercontract.GetCustomerByIDQuery{ID: n.CustomerID},
+	)
+	if err != nil {
+		return err
+	}
+
+	return h.notiService.SendEmail(customer.Email, "Order Canceled", map[string]any{
+		"order_id": n.OrderID,
+		"refund":   n.RefundAmount,
+	})
+}
@@ -2,17 +2,24 @@ package notification
 
 import (
 	"go-mma/modules/notification/internal/integration/customer"
+	"go-mma/modules/notification/internal/integration/order"
 	"go-mma/modules/notification/service"
 	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/inbox"
+	"go-mma/shared/common/mediator"
 	"go-mma/shared/common/module"
 	"go-mma/shared/common/registry"
+	"go-mma/shared/contract/ordercontract"
 	"go-mma/shared/messaging"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 )
 
 const (
 	NotificationServiceKey registry.ServiceKey = "NotificationService"
+
+	welcomeEmailHandlerName = "notification.welcome-email"
 )
 
 func NewModule(mCtx *module.ModuleContext) module.Module {
@@ -32,9 +39,25 @@ func (m *moduleImp) Init(reg registry.ServiceRegistry, eventBus eventbus.EventBu
 	m.notiSvc = service.NewNotificationService()
 
 	// subscribe to integration events
-	eventBus.Subscribe(messaging.CustomerCreatedIntegrationEventName, customer.NewWelcomeEmailHandler(m.notiSvc))
+	// ใช้ inbox ป้องกันการส่งอีเมลต้อนรับซ้ำเมื่อได้รับ event เดิมมากกว่า 1 ครั้ง
+	// SubscribeTyped จะคืน error ถ้า type ของ event ไม่ตรงกับที่ publisher ลงทะเบียนไว้
+	_, err := eventbus.SubscribeTyped(
+		eventBus,
+		messaging.CustomerCreatedIntegrationEventName,
+		inbox.WrapTyped(m.mCtx.Inbox, welcomeEmailHandlerName, customer.NewWelcomeEmailHandler(m.notiSvc)),
+		eventbus.WithHandlerName(welcomeEmailHandlerName),
+		eventbus.WithRetry(eventbus.DefaultRetryPolicy),
+		eventbus.WithSubscriptionMiddleware(eventbus.Timeout(10*time.Second)),
+	)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	// notification ภายใน process จากโมดูล order
+	return mediator.RegisterNotificationHandler[*ordercontract.OrderCanceledNotification](
+		m.mCtx.Mediator,
+		order.NewOrderCanceledEmailHandler(m.mCtx.Mediator, m.notiSvc),
+	)
 }
 
 func (m *moduleImp) Services() []registry.ProvidedService {
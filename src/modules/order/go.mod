@@ -10,11 +10,14 @@ replace go-mma/modules/notification v0.0.0 => ../../modules/notification
 
 replace go-mma/shared/contract/customercontract v0.0.0 => ../../shared/contract/customer-contract
 
+replace go-mma/shared/contract/ordercontract v0.0.0 => ../../shared/contract/order-contract
+
 require (
 	github.com/gofiber/fiber/v3 v3.0.0-beta.4
 	go-mma/modules/notification v0.0.0
 	go-mma/shared/common v0.0.0
 	go-mma/shared/contract/customercontract v0.0.0
+	go-mma/shared/contract/ordercontract v0.0.0
 )
 
 require (
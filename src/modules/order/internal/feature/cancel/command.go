@@ -1,5 +1,12 @@
 package cancel
 
+import "go-mma/shared/common/auth"
+
 type CancelOrderCommand struct {
 	ID int64 `json:"id"`
 }
+
+// RequiredPermissions ใช้ตรวจสิทธิ์ใน mediator.Authorization
+func (c *CancelOrderCommand) RequiredPermissions() []auth.Permission {
+	return []auth.Permission{"order:cancel"}
+}
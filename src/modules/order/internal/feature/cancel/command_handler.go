@@ -4,31 +4,31 @@ import (
 	"context"
 	"go-mma/modules/order/domainerrors"
 	"go-mma/modules/order/internal/repository"
-	"go-mma/shared/common/logger"
 	"go-mma/shared/common/mediator"
 	"go-mma/shared/common/storage/sqldb/transactor"
 	"go-mma/shared/contract/customercontract"
+	"go-mma/shared/contract/ordercontract"
 )
 
 type cancelOrderCommandHandler struct {
-	transactor transactor.Transactor
-	orderRepo  repository.OrderRepository
+	mediator  *mediator.Mediator
+	orderRepo repository.OrderRepository
 }
 
 func NewCancelOrderCommandHandler(
-	transactor transactor.Transactor,
+	mediator *mediator.Mediator,
 	orderRepo repository.OrderRepository) *cancelOrderCommandHandler {
 	return &cancelOrderCommandHandler{
-		transactor: transactor,
-		orderRepo:  orderRepo,
+		mediator:  mediator,
+		orderRepo: orderRepo,
 	}
 }
 
+// Handle ทำงานภายใน transaction ที่ mediator.Transaction เปิดให้
 func (h *cancelOrderCommandHandler) Handle(ctx context.Context, cmd *CancelOrderCommand) (*mediator.NoResponse, error) {
 	// ตรวจสอบ order id
 	order, err := h.orderRepo.FindByID(ctx, cmd.ID)
 	if err != nil {
-		logger.Log.Error(err.Error())
 		return nil, err
 	}
 
@@ -36,28 +36,34 @@ func (h *cancelOrderCommandHandler) Handle(ctx context.Context, cmd *CancelOrder
 		return nil, domainerrors.ErrNoOrderID
 	}
 
-	err = h.transactor.WithinTransaction(ctx, func(ctx context.Context, registerPostCommitHook func(transactor.PostCommitHook)) error {
-
-		// ยกเลิก order
-		if err := h.orderRepo.Cancel(ctx, order.ID); err != nil {
-			logger.Log.Error(err.Error())
-			return err
-		}
+	// ยกเลิก order
+	// ถ้ามีอีก request ยกเลิก order นี้ไปก่อน จะได้ concurrency conflict และไม่คืน credit ซ้ำ
+	if err := h.orderRepo.Cancel(ctx, order); err != nil {
+		return nil, err
+	}
 
-		// Business Logic: คืนยอด credit
-		if _, err = mediator.Send[*customercontract.ReleaseCreditCommand, *mediator.NoResponse](
-			ctx,
-			&customercontract.ReleaseCreditCommand{
-				CustomerID:   order.CustomerID,
-				CreditAmount: order.OrderTotal,
-			},
-		); err != nil {
-			return err
-		}
+	// Business Logic: คืนยอด credit
+	// ReleaseCreditCommand ถูกเปิด transaction ซ้อนใน transaction นี้
+	refund := int(order.OrderTotal.Amount())
+	if _, err = mediator.SendRequest[*customercontract.ReleaseCreditCommand, *mediator.NoResponse](
+		ctx,
+		h.mediator,
+		&customercontract.ReleaseCreditCommand{
+			CustomerID:   order.CustomerID,
+			CreditAmount: refund,
+		},
+	); err != nil {
+		return nil, err
+	}
 
-		return nil
+	// แจ้งโมดูลอื่นหลัง commit เท่านั้น error ของ handler จะถูก log โดย transactor
+	err = transactor.RegisterPostCommitHook(ctx, func(ctx context.Context) error {
+		return mediator.PublishNotification(ctx, h.mediator, &ordercontract.OrderCanceledNotification{
+			OrderID:      order.ID,
+			CustomerID:   order.CustomerID,
+			RefundAmount: refund,
+		})
 	})
-
 	if err != nil {
 		return nil, err
 	}
@@ -8,31 +8,34 @@ import (
 	"github.com/gofiber/fiber/v3"
 )
 
-func NewEndpoint(router fiber.Router, path string) {
-	router.Delete(path, cancelOrderHTTPHandler)
+func NewEndpoint(router fiber.Router, path string, med *mediator.Mediator) {
+	router.Delete(path, cancelOrderHTTPHandler(med))
 }
 
-func cancelOrderHTTPHandler(c fiber.Ctx) error {
-	// 1. อ่านค่า id จาก path param
-	id := c.Params("orderID")
-
-	// 2. ตรวจสอบรูปแบบ order id
-	orderID, err := strconv.Atoi(id)
-	if err != nil {
-		return errs.InputValidationError("invalid order id")
+func cancelOrderHTTPHandler(med *mediator.Mediator) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		// 1. อ่านค่า id จาก path param
+		id := c.Params("orderID")
+
+		// 2. ตรวจสอบรูปแบบ order id
+		orderID, err := strconv.Atoi(id)
+		if err != nil {
+			return errs.InputValidationError("invalid order id")
+		}
+
+		// 3. ส่งไปที่ Command Handler
+		_, err = mediator.SendRequest[*CancelOrderCommand, *mediator.NoResponse](
+			c.Context(),
+			med,
+			&CancelOrderCommand{ID: int64(orderID)},
+		)
+
+		// 4. จัดการ error จาก feature หากเกิดขึ้น
+		if err != nil {
+			return err
+		}
+
+		// 5. ตอบกลับ client
+		return c.SendStatus(fiber.StatusNoContent)
 	}
-
-	// 3. ส่งไปที่ Command Handler
-	_, err = mediator.Send[*CancelOrderCommand, *mediator.NoResponse](
-		c.Context(),
-		&CancelOrderCommand{ID: int64(orderID)},
-	)
-
-	// 4. จัดการ error จาก feature หากเกิดขึ้น
-	if err != nil {
-		return err
-	}
-
-	// 5. ตอบกลับ client
-	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -1,9 +1,16 @@
 package create
 
+import "go-mma/shared/common/auth"
+
 type CreateOrderCommand struct {
 	CreateOrderRequest
 }
 
+// RequiredPermissions ใช้ตรวจสิทธิ์ใน mediator.Authorization
+func (c *CreateOrderCommand) RequiredPermissions() []auth.Permission {
+	return []auth.Permission{"order:create"}
+}
+
 type CreateOrderCommandResult struct {
 	CreateOrderResponse
 }
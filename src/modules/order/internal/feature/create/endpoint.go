@@ -8,33 +8,36 @@ import (
 	"github.com/gofiber/fiber/v3"
 )
 
-func NewEndpoint(router fiber.Router, path string) {
-	router.Post(path, createOrderHTTPHandler)
+func NewEndpoint(router fiber.Router, path string, med *mediator.Mediator) {
+	router.Post(path, createOrderHTTPHandler(med))
 }
 
-func createOrderHTTPHandler(c fiber.Ctx) error {
-	// 1. รับ request body มาเป็น DTO
-	var req CreateOrderRequest
-	if err := c.Bind().Body(&req); err != nil {
-		return errs.InputValidationError(err.Error())
+func createOrderHTTPHandler(med *mediator.Mediator) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		// 1. รับ request body มาเป็น DTO
+		var req CreateOrderRequest
+		if err := c.Bind().Body(&req); err != nil {
+			return errs.InputValidationError(err.Error())
+		}
+
+		// 2. ตรวจสอบความถูกต้อง (validate)
+		if err := req.Validate(); err != nil {
+			return errs.InputValidationError(strings.Join(strings.Split(err.Error(), "\n"), ", "))
+		}
+
+		// 3. ส่งไปที่ Command Handler
+		resp, err := mediator.SendRequest[*CreateOrderCommand, *CreateOrderCommandResult](
+			c.Context(),
+			med,
+			&CreateOrderCommand{CreateOrderRequest: req},
+		)
+
+		// 4. จัดการ error จาก feature หากเกิดขึ้น
+		if err != nil {
+			return err
+		}
+
+		// 5. ตอบกลับ client
+		return c.Status(fiber.StatusCreated).JSON(resp)
 	}
-
-	// 2. ตรวจสอบความถูกต้อง (validate)
-	if err := req.Validate(); err != nil {
-		return errs.InputValidationError(strings.Join(strings.Split(err.Error(), "\n"), ", "))
-	}
-
-	// 3. ส่งไปที่ Command Handler
-	resp, err := mediator.Send[*CreateOrderCommand, *CreateOrderCommandResult](
-		c.Context(),
-		&CreateOrderCommand{CreateOrderRequest: req},
-	)
-
-	// 4. จัดการ error จาก feature หากเกิดขึ้น
-	if err != nil {
-		return err
-	}
-
-	// 5. ตอบกลับ client
-	return c.Status(fiber.StatusCreated).JSON(resp)
 }
@@ -1,19 +1,21 @@
 package model
 
 import (
+	"go-mma/shared/common/domain"
 	"go-mma/shared/common/idgen"
 	"time"
 )
 
 type Order struct {
-	ID         int64      `db:"id"`
-	CustomerID int64      `db:"customer_id"`
-	OrderTotal int        `db:"order_total"`
-	CreatedAt  time.Time  `db:"created_at"`
-	CanceledAt *time.Time `db:"canceled_at"`
+	ID               int64        `db:"id"`
+	CustomerID       int64        `db:"customer_id"`
+	OrderTotal       domain.Money `db:"order_total"`
+	CreatedAt        time.Time    `db:"created_at"`
+	CanceledAt       *time.Time   `db:"canceled_at"`
+	domain.Aggregate              // มี version ใช้ตรวจ optimistic concurrency
 }
 
-func NewOrder(customerID int64, orderTotal int) *Order {
+func NewOrder(customerID int64, orderTotal domain.Money) *Order {
 	return &Order{
 		ID:         idgen.GenerateTimeRandomID(),
 		CustomerID: customerID,
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"go-mma/modules/order/internal/model"
 	"go-mma/shared/common/errs"
+	"go-mma/shared/common/storage/sqldb/criteria"
 	"go-mma/shared/common/storage/sqldb/transactor"
 	"time"
 )
@@ -13,7 +14,10 @@ import (
 type OrderRepository interface {
 	Create(ctx context.Context, order *model.Order) error
 	FindByID(ctx context.Context, id int64) (*model.Order, error)
-	Cancel(ctx context.Context, id int64) error
+	// Find คืน order ที่ตรงกับ c เช่น criteria.Where(OrdersOfCustomer(id), OrdersCreatedAfter(t)).OrderBy("created_at", criteria.Desc)
+	Find(ctx context.Context, c *criteria.Criteria) ([]*model.Order, error)
+	// Cancel คืน errs.ErrConcurrency ถ้า order ถูกแก้ไขไปแล้วหลังจากที่อ่านมา
+	Cancel(ctx context.Context, order *model.Order) error
 }
 
 type orderRepository struct {
@@ -46,36 +50,44 @@ func (r *orderRepository) Create(ctx context.Context, m *model.Order) error {
 }
 
 func (r *orderRepository) FindByID(ctx context.Context, id int64) (*model.Order, error) {
-	query := `
-	SELECT *
-	FROM public.orders
-	WHERE id = $1
-	AND canceled_at IS NULL
-`
 	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 	defer cancel()
 
-	var order model.Order
-	err := r.dbCtx(ctx).QueryRowxContext(ctx, query, id).StructScan(&order)
+	order, err := criteria.First[model.Order](ctx, r.dbCtx, "public.orders", criteria.Where(OrderByID(id), ActiveOrders()))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
 		return nil, errs.HandleDBError(fmt.Errorf("failed to get order by ID: %w", err))
 	}
-	return &order, nil
+	return order, nil
+}
+
+func (r *orderRepository) Find(ctx context.Context, c *criteria.Criteria) ([]*model.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	orders, err := criteria.Select[model.Order](ctx, r.dbCtx, "public.orders", c)
+	if err != nil {
+		return nil, errs.HandleDBError(fmt.Errorf("failed to find orders: %w", err))
+	}
+	return orders, nil
 }
 
-func (r *orderRepository) Cancel(ctx context.Context, id int64) error {
+func (r *orderRepository) Cancel(ctx context.Context, m *model.Order) error {
 	query := `
 	UPDATE public.orders
-	SET canceled_at = current_timestamp
+	SET canceled_at = current_timestamp,
+		version = version + 1
 	WHERE id = $1
+	AND version = $2
+	RETURNING *
 `
 	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 	defer cancel()
-	_, err := r.dbCtx(ctx).ExecContext(ctx, query, id)
+
+	err := r.dbCtx(ctx).QueryRowxContext(ctx, query, m.ID, m.Version).StructScan(m)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return errs.ConcurrencyConflictError(fmt.Sprintf("order %d was modified by another request", m.ID))
+		}
 		return errs.HandleDBError(fmt.Errorf("failed to cancel order: %w", err))
 	}
 	return nil
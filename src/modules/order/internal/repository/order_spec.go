@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"go-mma/shared/common/storage/sqldb/criteria"
+	"time"
+)
+
+// เงื่อนไขของ order สำหรับ OrderRepository.Find ประกอบกันด้วย criteria.Where, criteria.And และ criteria.Or
+
+func OrderByID(id int64) criteria.Predicate {
+	return criteria.Eq("id", id)
+}
+
+func OrdersOfCustomer(customerID int64) criteria.Predicate {
+	return criteria.Eq("customer_id", customerID)
+}
+
+func OrdersCreatedAfter(t time.Time) criteria.Predicate {
+	return criteria.Gt("created_at", t)
+}
+
+// ActiveOrders คือ order ที่ยังไม่ถูกยกเลิก
+func ActiveOrders() criteria.Predicate {
+	return criteria.IsNull("canceled_at")
+}
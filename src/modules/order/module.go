@@ -1,6 +1,7 @@
 package order
 
 import (
+	"errors"
 	"go-mma/modules/order/internal/feature/cancel"
 	"go-mma/modules/order/internal/feature/create"
 	"go-mma/modules/order/internal/repository"
@@ -8,6 +9,7 @@ import (
 	"go-mma/shared/common/mediator"
 	"go-mma/shared/common/module"
 	"go-mma/shared/common/registry"
+	"go-mma/shared/contract/ordercontract"
 
 	notiModule "go-mma/modules/notification"
 	notiService "go-mma/modules/notification/service"
@@ -37,14 +39,19 @@ func (m *moduleImp) Init(reg registry.ServiceRegistry, eventBus eventbus.EventBu
 
 	repo := repository.NewOrderRepository(m.mCtx.DBCtx)
 
-	mediator.Register(create.NewCreateOrderCommandHandler(m.mCtx.Transactor, repo, notiSvc))
-	mediator.Register(cancel.NewCancelOrderCommandHandler(m.mCtx.Transactor, repo))
+	// command ที่แก้ไขข้อมูลทำงานภายใน transaction ที่ mediator เปิดให้
+	tx := mediator.WithBehaviors(mediator.Transaction(m.mCtx.Transactor))
 
-	return nil
+	// handler ของ OrderCanceledNotification ทำงานหลัง commit และไม่ใช้ transaction ร่วมกัน จึงเรียกพร้อมกันได้
+	return errors.Join(
+		mediator.SetPublishStrategyFor[*ordercontract.OrderCanceledNotification](m.mCtx.Mediator, mediator.Parallel()),
+		mediator.RegisterHandler(m.mCtx.Mediator, create.NewCreateOrderCommandHandler(m.mCtx.Mediator, repo, notiSvc), tx),
+		mediator.RegisterHandler(m.mCtx.Mediator, cancel.NewCancelOrderCommandHandler(m.mCtx.Mediator, repo), tx),
+	)
 }
 
 func (m *moduleImp) RegisterRoutes(router fiber.Router) {
 	orders := router.Group("/orders")
-	create.NewEndpoint(orders, "")
-	cancel.NewEndpoint(orders, "/:orderID")
+	create.NewEndpoint(orders, "", m.mCtx.Mediator)
+	cancel.NewEndpoint(orders, "/:orderID", m.mCtx.Mediator)
 }
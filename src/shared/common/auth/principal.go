@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Permission คือสิทธิ์ในการทำงานหนึ่งอย่าง ตั้งชื่อแบบ "resource:action" เช่น "order:create"
+type Permission string
+
+// AllPermissions ให้สิทธิ์ทุกอย่าง ใช้กับ role ผู้ดูแลระบบ
+const AllPermissions Permission = "*"
+
+// header ที่ใช้ส่งตัวตนของผู้เรียก ทั้งจาก API gateway และระหว่าง process ของระบบเอง
+// header ตัวตนเชื่อถือได้เฉพาะเมื่อมี HeaderServiceSecret ที่ถูกต้องมาด้วย
+const (
+	HeaderUserID        = "X-User-ID"
+	HeaderUserRoles     = "X-User-Roles" // คั่นด้วย comma
+	HeaderTenantID      = "X-Tenant-ID"
+	HeaderServiceSecret = "X-Service-Secret" // shared secret ที่มีเฉพาะ API gateway และ process ภายในระบบ
+)
+
+// Principal คือผู้เรียกใช้งานของ request ปัจจุบัน
+type Principal struct {
+	UserID      string
+	TenantID    string
+	Roles       []string
+	Permissions []Permission
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+func (p *Principal) HasPermission(perm Permission) bool {
+	return slices.Contains(p.Permissions, AllPermissions) || slices.Contains(p.Permissions, perm)
+}
+
+// RolePermissions จับคู่ role กับสิทธิ์ที่ role นั้นได้รับ
+type RolePermissions map[string][]Permission
+
+// Resolve รวมสิทธิ์ของทุก role โดยไม่ซ้ำกัน
+func (rp RolePermissions) Resolve(roles []string) []Permission {
+	var perms []Permission
+	for _, role := range roles {
+		for _, perm := range rp[role] {
+			if !slices.Contains(perms, perm) {
+				perms = append(perms, perm)
+			}
+		}
+	}
+	return perms
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal แนบ principal ไปกับ context ให้ mediator และ handler ใช้ต่อ
+func ContextWithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext คืน principal ของ request ปัจจุบัน ถ้าไม่มีจะคืน false
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
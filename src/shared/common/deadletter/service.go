@@ -0,0 +1,63 @@
+package deadletter
+
+import (
+	"context"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/eventbus"
+)
+
+var (
+	ErrDeadLetterNotFound = errs.ResourceNotFoundError("the dead-letter event with given id was not found")
+	ErrAlreadyReplayed    = errs.ConflictError("the dead-letter event was already replayed")
+)
+
+// Service ใช้ดูรายการ และ replay event ที่ค้างอยู่ใน dead-letter store
+type Service interface {
+	List(ctx context.Context, limit int, offset int) ([]*eventbus.DeadLetter, error)
+	Replay(ctx context.Context, id int64) error
+}
+
+type service struct {
+	store    eventbus.DeadLetterStore
+	eventBus eventbus.EventBus
+	codec    eventbus.Codec
+}
+
+func NewService(store eventbus.DeadLetterStore, eventBus eventbus.EventBus, codec eventbus.Codec) Service {
+	return &service{
+		store:    store,
+		eventBus: eventBus,
+		codec:    codec,
+	}
+}
+
+func (s *service) List(ctx context.Context, limit int, offset int) ([]*eventbus.DeadLetter, error) {
+	return s.store.List(ctx, limit, offset)
+}
+
+// Replay ส่ง event กลับไปให้ handler ตัวเดิมแบบ synchronous
+// ถ้าสำเร็จจะบันทึกเวลาที่ replay ไว้ ถ้าไม่สำเร็จจะคืน error ให้ผู้เรียก
+func (s *service) Replay(ctx context.Context, id int64) error {
+	dl, err := s.store.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if dl == nil {
+		return ErrDeadLetterNotFound
+	}
+	if dl.ReplayedAt != nil {
+		return ErrAlreadyReplayed
+	}
+
+	event, env, err := s.codec.Decode(dl.Payload)
+	if err != nil {
+		return errs.OperationFailedError("failed to decode dead-letter event", err)
+	}
+
+	ctx = eventbus.ContextWithMetadata(ctx, env.Metadata)
+	if err := s.eventBus.Deliver(ctx, dl.HandlerName, event); err != nil {
+		return errs.OperationFailedError("failed to replay dead-letter event: "+err.Error(), err)
+	}
+
+	return s.store.MarkReplayed(ctx, dl.ID)
+}
@@ -0,0 +1,97 @@
+package deadletter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/storage/sqldb/transactor"
+	"time"
+)
+
+type sqlStore struct {
+	dbCtx transactor.DBContext
+}
+
+func NewStore(dbCtx transactor.DBContext) eventbus.DeadLetterStore {
+	return &sqlStore{
+		dbCtx: dbCtx,
+	}
+}
+
+func (s *sqlStore) Save(ctx context.Context, dl *eventbus.DeadLetter) error {
+	query := `
+	INSERT INTO public.dead_letters (event_id, event_name, handler_name, payload, error, attempts)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING *
+	`
+
+	// dead-letter ต้องถูกบันทึกเสมอ แม้ transaction ของผู้เรียกจะ rollback
+	ctx = transactor.WithoutTransaction(ctx)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err := s.dbCtx(ctx).
+		QueryRowxContext(ctx, query, dl.EventID, dl.EventName, dl.HandlerName, dl.Payload, dl.Error, dl.Attempts).
+		StructScan(dl)
+	if err != nil {
+		return errs.HandleDBError(fmt.Errorf("failed to insert dead-letter event: %w", err))
+	}
+	return nil
+}
+
+func (s *sqlStore) List(ctx context.Context, limit int, offset int) ([]*eventbus.DeadLetter, error) {
+	query := `
+	SELECT *
+	FROM public.dead_letters
+	ORDER BY id DESC
+	LIMIT $1 OFFSET $2
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	dls := make([]*eventbus.DeadLetter, 0)
+	if err := s.dbCtx(ctx).SelectContext(ctx, &dls, query, limit, offset); err != nil {
+		return nil, errs.HandleDBError(fmt.Errorf("failed to list dead-letter events: %w", err))
+	}
+	return dls, nil
+}
+
+func (s *sqlStore) FindByID(ctx context.Context, id int64) (*eventbus.DeadLetter, error) {
+	query := `
+	SELECT *
+	FROM public.dead_letters
+	WHERE id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var dl eventbus.DeadLetter
+	err := s.dbCtx(ctx).QueryRowxContext(ctx, query, id).StructScan(&dl)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errs.HandleDBError(fmt.Errorf("failed to get dead-letter event by ID: %w", err))
+	}
+	return &dl, nil
+}
+
+func (s *sqlStore) MarkReplayed(ctx context.Context, id int64) error {
+	query := `
+	UPDATE public.dead_letters
+	SET replayed_at = current_timestamp
+	WHERE id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if _, err := s.dbCtx(ctx).ExecContext(ctx, query, id); err != nil {
+		return errs.HandleDBError(fmt.Errorf("failed to mark dead-letter event as replayed: %w", err))
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package domain
 
 type Aggregate struct {
+	// Version คือ version ของ aggregate ตอนที่อ่านจากฐานข้อมูล
+	// repository ใช้ตรวจว่าไม่มีใครแก้ไขไปก่อนระหว่างที่ทำงาน (optimistic concurrency)
+	Version      int `db:"version"`
 	domainEvents []DomainEvent
 }
 
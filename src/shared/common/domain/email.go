@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+var (
+	ErrEmailRequired = errors.New("email is required")
+	ErrInvalidEmail  = errors.New("email is invalid")
+)
+
+// Email คืออีเมลที่ผ่านการตรวจสอบแล้ว เก็บเป็นตัวพิมพ์เล็กไม่มีช่องว่าง จึงเทียบกันได้ตรงๆ
+type Email struct {
+	value string
+}
+
+// NewEmail ตรวจสอบและ normalize อีเมล รับเฉพาะที่อยู่อย่างเดียว ไม่รับรูปแบบ "Name <addr>"
+func NewEmail(s string) (Email, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return Email{}, ErrEmailRequired
+	}
+
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return Email{}, fmt.Errorf("%w: %q", ErrInvalidEmail, s)
+	}
+	return Email{value: s}, nil
+}
+
+func (e Email) String() string {
+	return e.value
+}
+
+func (e Email) IsZero() bool {
+	return e.value == ""
+}
+
+func (e Email) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.value)
+}
+
+func (e *Email) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	email, err := NewEmail(s)
+	if err != nil {
+		return err
+	}
+	*e = email
+	return nil
+}
+
+func (e Email) Value() (driver.Value, error) {
+	if e.IsZero() {
+		return nil, ErrEmailRequired
+	}
+	return e.value, nil
+}
+
+// Scan ตรวจสอบและ normalize แบบเดียวกับ NewEmail ข้อมูลในฐานข้อมูลที่ไม่ผ่านจะคืน error แทนที่จะได้ Email ที่ไม่ถูกต้อง
+func (e *Email) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Email", src)
+	}
+
+	email, err := NewEmail(s)
+	if err != nil {
+		return err
+	}
+	*e = email
+	return nil
+}
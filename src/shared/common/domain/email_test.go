@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNewEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "valid", input: "cust@example.com", want: "cust@example.com"},
+		{name: "normalizes case and spaces", input: "  Cust@Example.COM \n", want: "cust@example.com"},
+		{name: "empty", input: "", wantErr: ErrEmailRequired},
+		{name: "only spaces", input: "   ", wantErr: ErrEmailRequired},
+		{name: "missing domain", input: "cust@", wantErr: ErrInvalidEmail},
+		{name: "missing at", input: "cust.example.com", wantErr: ErrInvalidEmail},
+		{name: "display name", input: "Cust <cust@example.com>", wantErr: ErrInvalidEmail},
+		{name: "two addresses", input: "a@example.com, b@example.com", wantErr: ErrInvalidEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEmail(tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Fatalf("NewEmail(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailJSON(t *testing.T) {
+	var got Email
+	if err := json.Unmarshal([]byte(`"Cust@Example.com"`), &got); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"cust@example.com"` {
+		t.Fatalf("marshal = %s, want normalized email", data)
+	}
+
+	if err := json.Unmarshal([]byte(`"not an email"`), &got); !errors.Is(err, ErrInvalidEmail) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidEmail)
+	}
+}
+
+func TestEmailSQL(t *testing.T) {
+	if _, err := (Email{}).Value(); !errors.Is(err, ErrEmailRequired) {
+		t.Fatalf("Value of zero Email err = %v, want %v", err, ErrEmailRequired)
+	}
+
+	tests := []struct {
+		name    string
+		src     any
+		want    string
+		wantErr error
+	}{
+		{name: "string", src: "cust@example.com", want: "cust@example.com"},
+		{name: "bytes are normalized", src: []byte(" Cust@Example.com"), want: "cust@example.com"},
+		{name: "invalid", src: "cust@", wantErr: ErrInvalidEmail},
+		{name: "empty", src: "", wantErr: ErrEmailRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Email
+			err := got.Scan(tt.src)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if !got.IsZero() {
+					t.Fatalf("Scan(%#v) left %q, want zero Email", tt.src, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Fatalf("Scan(%#v) = %q, want %q", tt.src, got, tt.want)
+			}
+
+			v, err := got.Value()
+			if err != nil || v != tt.want {
+				t.Fatalf("Value = %#v, %v, want %q", v, err, tt.want)
+			}
+		})
+	}
+
+	var got Email
+	if err := got.Scan(42); err == nil {
+		t.Fatal("Scan(int) err = nil, want error")
+	}
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sync"
 )
 
@@ -16,35 +17,132 @@ type DomainEventHandler interface {
 }
 
 // DomainEventDispatcher is the centralized event dispatcher
+// handler แต่ละตัวอยู่ใน phase เดียว: หลัง commit (ค่าเริ่มต้น) หรือภายใน transaction (InTransaction)
 type DomainEventDispatcher interface {
-	Register(eventType EventName, handler DomainEventHandler)
+	Register(eventType EventName, handler DomainEventHandler, opts ...RegisterOption)
+	// Dispatch เรียก handler ที่ทำงานหลัง commit
 	Dispatch(ctx context.Context, events []DomainEvent) error
+	// DispatchInTransaction เรียก handler ที่ลงทะเบียนด้วย InTransaction ctx ต้องอยู่ภายใน transaction
+	DispatchInTransaction(ctx context.Context, events []DomainEvent) error
+}
+
+type RegisterOption func(*registerConfig)
+
+type registerConfig struct {
+	inTransaction bool
+}
+
+// InTransaction ให้ handler ทำงานภายใน transaction ก่อน commit
+// ใช้กับ handler ที่ต้องเขียนข้อมูลพร้อมกับ aggregate เช่น outbox หรือ ledger ถ้าคืน error จะ rollback ทั้ง transaction
+func InTransaction() RegisterOption {
+	return func(c *registerConfig) {
+		c.inTransaction = true
+	}
 }
 
 // simpleDomainEventDispatcher manages event handlers
 type simpleDomainEventDispatcher struct {
-	handlers map[EventName][]DomainEventHandler
-	mu       sync.RWMutex
+	handlers   map[EventName][]DomainEventHandler
+	txHandlers map[EventName][]DomainEventHandler
+	types      map[EventName]reflect.Type
+	declared   EventTypeDeclarations
+	mu         sync.RWMutex
+}
+
+type DispatcherOption func(*simpleDomainEventDispatcher)
+
+// WithEventTypes ตรวจ type ของ handler ตอน RegisterTyped และ type ของ event ตอน dispatch กับ type ที่ประกาศไว้
+// ทำให้ handler ที่รับ type ไม่ตรงกับที่ aggregate raise (เช่น value กับ pointer) ผิดพลาดตั้งแต่ตอนลงทะเบียน
+func WithEventTypes(types EventTypeDeclarations) DispatcherOption {
+	return func(d *simpleDomainEventDispatcher) {
+		d.declared = types
+	}
 }
 
 // NewSimpleDomainEventDispatcher creates a new dispatcher
-func NewSimpleDomainEventDispatcher() DomainEventDispatcher {
-	return &simpleDomainEventDispatcher{handlers: make(map[EventName][]DomainEventHandler)}
+func NewSimpleDomainEventDispatcher(opts ...DispatcherOption) DomainEventDispatcher {
+	d := &simpleDomainEventDispatcher{
+		handlers:   make(map[EventName][]DomainEventHandler),
+		txHandlers: make(map[EventName][]DomainEventHandler),
+		types:      make(map[EventName]reflect.Type),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 // Register handler สำหรับแต่ละ event name
-func (d *simpleDomainEventDispatcher) Register(eventType EventName, handler DomainEventHandler) {
+func (d *simpleDomainEventDispatcher) Register(eventType EventName, handler DomainEventHandler, opts ...RegisterOption) {
+	cfg := &registerConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	if cfg.inTransaction {
+		d.txHandlers[eventType] = append(d.txHandlers[eventType], handler)
+		return
+	}
 	d.handlers[eventType] = append(d.handlers[eventType], handler)
 }
 
-// Dispatch จะ loop event และ call handler ที่ลงทะเบียนไว้
+func (d *simpleDomainEventDispatcher) bindType(eventType EventName, t reflect.Type) error {
+	if d.declared != nil {
+		return d.acceptsDeclared(eventType, t)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if bound, ok := d.types[eventType]; ok && bound != t {
+		return fmt.Errorf("%w: %s is bound to %s, handler expects %s", ErrInvalidEvent, eventType, bound, t)
+	}
+	d.types[eventType] = t
+	return nil
+}
+
+// acceptsDeclared ตรวจว่า event ที่ประกาศเป็น name ส่งให้ handler ที่รับ t ได้ (t เป็น interface ที่ type นั้น implement ก็ได้)
+func (d *simpleDomainEventDispatcher) acceptsDeclared(name EventName, t reflect.Type) error {
+	declared, ok := d.declared.DeclaredType(name)
+	if !ok {
+		return fmt.Errorf("%w: %s is not declared", ErrInvalidEvent, name)
+	}
+
+	if t.Kind() == reflect.Interface {
+		if !declared.Implements(t) {
+			return fmt.Errorf("%w: %s is declared as %s, which does not implement %s", ErrInvalidEvent, name, declared, t)
+		}
+		return nil
+	}
+	if declared != t {
+		return fmt.Errorf("%w: %s is declared as %s, got %s", ErrInvalidEvent, name, declared, t)
+	}
+	return nil
+}
+
+// Dispatch จะ loop event ตามลำดับ และ call handler ตามลำดับที่ลงทะเบียนไว้ หยุดที่ error แรก
 func (d *simpleDomainEventDispatcher) Dispatch(ctx context.Context, events []DomainEvent) error {
+	return d.dispatch(ctx, d.handlers, events)
+}
+
+// DispatchInTransaction ทำงานแบบเดียวกับ Dispatch แต่เรียกเฉพาะ handler ของ InTransaction
+func (d *simpleDomainEventDispatcher) DispatchInTransaction(ctx context.Context, events []DomainEvent) error {
+	return d.dispatch(ctx, d.txHandlers, events)
+}
+
+func (d *simpleDomainEventDispatcher) dispatch(ctx context.Context, registered map[EventName][]DomainEventHandler, events []DomainEvent) error {
 	for _, event := range events {
+		if d.declared != nil {
+			if err := d.acceptsDeclared(event.EventName(), reflect.TypeOf(event)); err != nil {
+				return err
+			}
+		}
+
 		d.mu.RLock()
-		handlers := append([]DomainEventHandler(nil), d.handlers[event.EventName()]...) // เป็นการ copy slice เพื่อหลีกเลี่ยง race ถ้า handler ถูกแก้ไขระหว่าง dispatch
+		handlers := append([]DomainEventHandler(nil), registered[event.EventName()]...) // เป็นการ copy slice เพื่อหลีกเลี่ยง race ถ้า handler ถูกแก้ไขระหว่าง dispatch
 		d.mu.RUnlock()
 
 		for _, handler := range handlers {
@@ -0,0 +1,287 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var (
+	ErrStreamNotFound   = errors.New("event stream not found")
+	ErrUnknownEventType = errors.New("unknown event type")
+)
+
+// EventSourced คือ aggregate ที่สร้าง state ขึ้นใหม่จาก domain event ของตัวเอง แทนการเก็บเป็นแถวที่แก้ไขได้
+// ต้อง embed EventSourcedAggregate
+type EventSourced interface {
+	StreamID() string
+	// Apply เปลี่ยน state ตาม event ห้ามมี side effect และห้ามตรวจ business rule ซ้ำ
+	// เพราะถูกเรียกซ้ำทุกครั้งที่โหลด aggregate
+	Apply(event DomainEvent) error
+	eventSourced() *EventSourcedAggregate
+}
+
+// Snapshotter คือ EventSourced ที่บันทึก state เป็น snapshot ได้ เพื่อไม่ต้อง apply event ทั้ง stream ทุกครั้ง
+type Snapshotter interface {
+	Snapshot() ([]byte, error)
+	RestoreSnapshot(state []byte) error
+}
+
+// EventSourcedAggregate เก็บ event ใหม่ที่ยังไม่ถูกบันทึกลง EventStore
+// Version คือจำนวน event ใน stream ตอนที่โหลดมา (0 คือ stream ใหม่)
+// event ที่ Raise จะถูกเก็บไว้ใน domain events ด้วย จึงใช้กับ TrackAggregate และ dispatcher ได้เหมือนเดิม
+type EventSourcedAggregate struct {
+	Aggregate
+	changes []DomainEvent
+}
+
+// Raise เปลี่ยน state ของ self ด้วย event แล้วเก็บ event ไว้รอบันทึก
+// self คือ aggregate ที่ embed a อยู่
+func (a *EventSourcedAggregate) Raise(self EventSourced, event DomainEvent) error {
+	if err := self.Apply(event); err != nil {
+		return err
+	}
+	a.changes = append(a.changes, event)
+	a.AddDomainEvent(event)
+	return nil
+}
+
+// Changes คืน event ที่ยังไม่ถูกบันทึก
+func (a *EventSourcedAggregate) Changes() []DomainEvent {
+	return a.changes
+}
+
+func (a *EventSourcedAggregate) eventSourced() *EventSourcedAggregate {
+	return a
+}
+
+// RecordedEvent คือ event ที่ถูกบันทึกใน stream
+type RecordedEvent struct {
+	StreamID   string
+	Version    int // ลำดับของ event ใน stream เริ่มที่ 1
+	EventName  EventName
+	Payload    []byte
+	OccurredAt time.Time
+}
+
+// Snapshot คือ state ของ aggregate ณ version หนึ่ง
+type Snapshot struct {
+	StreamID string
+	Version  int
+	State    []byte
+}
+
+// EventStore เก็บ event ของ aggregate แยกตาม stream
+type EventStore interface {
+	// Append ต่อ event ท้าย stream โดย version ล่าสุดของ stream ต้องเท่ากับ expectedVersion
+	// ไม่เช่นนั้นจะคืน errs.ErrConcurrency
+	Append(ctx context.Context, streamID string, expectedVersion int, events []RecordedEvent) error
+	// Load คืน event ที่ version มากกว่า afterVersion เรียงตาม version
+	Load(ctx context.Context, streamID string, afterVersion int) ([]RecordedEvent, error)
+	// LoadSnapshot คืน snapshot ล่าสุดของ stream ถ้าไม่มีจะคืน nil
+	LoadSnapshot(ctx context.Context, streamID string) (*Snapshot, error)
+	SaveSnapshot(ctx context.Context, snapshot *Snapshot) error
+}
+
+// EventTypes จับคู่ชื่อ event กับ type สำหรับ decode event ที่โหลดจาก EventStore
+type EventTypes struct {
+	mu    sync.RWMutex
+	types map[EventName]reflect.Type
+}
+
+func NewEventTypes() *EventTypes {
+	return &EventTypes{
+		types: make(map[EventName]reflect.Type),
+	}
+}
+
+// RegisterEventType ลงทะเบียน T กับชื่อ event เดียวกับที่ใช้ใน BaseDomainEvent
+// ควรเรียกที่เดียวกับที่ประกาศ event เพื่อให้ EventStore และ dispatcher (WithEventTypes) ใช้ข้อมูลชุดเดียวกัน
+func RegisterEventType[T DomainEvent](r *EventTypes, name EventName) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := reflect.TypeFor[T]()
+	if bound, ok := r.types[name]; ok && bound != t {
+		return fmt.Errorf("%w: %s is bound to %s, got %s", ErrInvalidEvent, name, bound, t)
+	}
+	r.types[name] = t
+	return nil
+}
+
+// MustRegisterEventType เหมือน RegisterEventType แต่ panic ถ้าผิดพลาด ใช้ใน init() ของแพ็กเกจที่ประกาศ event
+func MustRegisterEventType[T DomainEvent](r *EventTypes, name EventName) {
+	if err := RegisterEventType[T](r, name); err != nil {
+		panic(err)
+	}
+}
+
+// DeclaredType คืน type ที่ลงทะเบียนไว้กับ name ทำให้ใช้ EventTypes กับ WithEventTypes ของ dispatcher ได้
+func (r *EventTypes) DeclaredType(name EventName) (reflect.Type, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.types[name]
+	return t, ok
+}
+
+func (r *EventTypes) decode(name EventName, payload []byte) (DomainEvent, error) {
+	r.mu.RLock()
+	t, ok := r.types[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, name)
+	}
+
+	var ptr reflect.Value
+	if t.Kind() == reflect.Pointer {
+		ptr = reflect.New(t.Elem())
+	} else {
+		ptr = reflect.New(t)
+	}
+	if err := json.Unmarshal(payload, ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode event %s: %w", name, err)
+	}
+	if t.Kind() == reflect.Pointer {
+		return ptr.Interface().(DomainEvent), nil
+	}
+	return ptr.Elem().Interface().(DomainEvent), nil
+}
+
+// EventSourcedRepository โหลดและบันทึก aggregate ผ่าน EventStore
+type EventSourcedRepository[T EventSourced] struct {
+	store         EventStore
+	types         *EventTypes
+	newAggregate  func() T
+	snapshotEvery int
+}
+
+type EventSourcedRepositoryOption func(*eventSourcedConfig)
+
+type eventSourcedConfig struct {
+	snapshotEvery int
+}
+
+// WithSnapshotEvery บันทึก snapshot ทุก n event (aggregate ต้องเป็น Snapshotter) ค่า 0 คือไม่ทำ snapshot
+func WithSnapshotEvery(n int) EventSourcedRepositoryOption {
+	return func(c *eventSourcedConfig) {
+		c.snapshotEvery = n
+	}
+}
+
+// NewEventSourcedRepository สร้าง repository ของ T โดย newAggregate คืน aggregate เปล่าสำหรับ apply event
+func NewEventSourcedRepository[T EventSourced](store EventStore, types *EventTypes, newAggregate func() T, opts ...EventSourcedRepositoryOption) *EventSourcedRepository[T] {
+	cfg := &eventSourcedConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &EventSourcedRepository[T]{
+		store:         store,
+		types:         types,
+		newAggregate:  newAggregate,
+		snapshotEvery: cfg.snapshotEvery,
+	}
+}
+
+// Load สร้าง aggregate จาก snapshot ล่าสุด (ถ้ามี) แล้ว apply event ที่ตามมา
+// คืน ErrStreamNotFound ถ้า stream ยังไม่มี event
+func (r *EventSourcedRepository[T]) Load(ctx context.Context, streamID string) (T, error) {
+	agg := r.newAggregate()
+	base := agg.eventSourced()
+
+	if s, ok := any(agg).(Snapshotter); ok && r.snapshotEvery > 0 {
+		snapshot, err := r.store.LoadSnapshot(ctx, streamID)
+		if err != nil {
+			return agg, err
+		}
+		if snapshot != nil {
+			if err := s.RestoreSnapshot(snapshot.State); err != nil {
+				return agg, fmt.Errorf("failed to restore snapshot of %s: %w", streamID, err)
+			}
+			base.Version = snapshot.Version
+		}
+	}
+
+	records, err := r.store.Load(ctx, streamID, base.Version)
+	if err != nil {
+		return agg, err
+	}
+	if base.Version == 0 && len(records) == 0 {
+		return agg, fmt.Errorf("%w: %s", ErrStreamNotFound, streamID)
+	}
+
+	for _, rec := range records {
+		event, err := r.types.decode(rec.EventName, rec.Payload)
+		if err != nil {
+			return agg, err
+		}
+		if err := agg.Apply(event); err != nil {
+			return agg, fmt.Errorf("failed to apply event %d of %s: %w", rec.Version, streamID, err)
+		}
+		base.Version = rec.Version
+	}
+	return agg, nil
+}
+
+// Save บันทึก event ใหม่ของ agg ต่อจาก version ที่โหลดมา
+// ถ้ามีการบันทึก stream เดียวกันไปก่อนจะคืน errs.ErrConcurrency จาก EventStore
+func (r *EventSourcedRepository[T]) Save(ctx context.Context, agg T) error {
+	base := agg.eventSourced()
+	if len(base.changes) == 0 {
+		return nil
+	}
+
+	streamID := agg.StreamID()
+	records := make([]RecordedEvent, 0, len(base.changes))
+	for i, event := range base.changes {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode event %s: %w", event.EventName(), err)
+		}
+		records = append(records, RecordedEvent{
+			StreamID:   streamID,
+			Version:    base.Version + i + 1,
+			EventName:  event.EventName(),
+			Payload:    payload,
+			OccurredAt: event.OccurredAt(),
+		})
+	}
+
+	if err := r.store.Append(ctx, streamID, base.Version, records); err != nil {
+		return err
+	}
+
+	prev := base.Version
+	base.Version += len(records)
+	base.changes = nil
+
+	return r.snapshot(ctx, agg, prev)
+}
+
+// snapshot บันทึก snapshot เมื่อ version ข้ามรอบของ snapshotEvery
+func (r *EventSourcedRepository[T]) snapshot(ctx context.Context, agg T, prev int) error {
+	s, ok := any(agg).(Snapshotter)
+	if !ok || r.snapshotEvery <= 0 {
+		return nil
+	}
+
+	version := agg.eventSourced().Version
+	if version/r.snapshotEvery == prev/r.snapshotEvery {
+		return nil
+	}
+
+	state, err := s.Snapshot()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot %s: %w", agg.StreamID(), err)
+	}
+	return r.store.SaveSnapshot(ctx, &Snapshot{
+		StreamID: agg.StreamID(),
+		Version:  version,
+		State:    state,
+	})
+}
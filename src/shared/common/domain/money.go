@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidCurrency  = errors.New("currency must be a 3-letter ISO 4217 code")
+	ErrCurrencyMismatch = errors.New("money currencies do not match")
+)
+
+// Currency คือรหัสสกุลเงินตาม ISO 4217 เช่น "THB"
+type Currency string
+
+// DefaultCurrency คือสกุลเงินของระบบ ใช้กับยอดเงินในฐานข้อมูลและ JSON ที่ส่งมาเป็นตัวเลขอย่างเดียว
+const DefaultCurrency Currency = "THB"
+
+func (c Currency) validate() error {
+	if len(c) != 3 {
+		return fmt.Errorf("%w: %q", ErrInvalidCurrency, string(c))
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return fmt.Errorf("%w: %q", ErrInvalidCurrency, string(c))
+		}
+	}
+	return nil
+}
+
+// Money คือจำนวนเงินในหน่วยย่อยที่สุดของสกุลเงิน (เช่น สตางค์) เป็นจำนวนเต็มเพื่อไม่ให้มีปัญหาทศนิยม
+// การคำนวณระหว่างสกุลเงินต่างกันจะคืน ErrCurrencyMismatch
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+func NewMoney(amount int64, currency Currency) (Money, error) {
+	if err := currency.validate(); err != nil {
+		return Money{}, err
+	}
+	return Money{amount: amount, currency: currency}, nil
+}
+
+// NewDefaultMoney สร้าง Money ในสกุล DefaultCurrency
+func NewDefaultMoney(amount int64) Money {
+	return Money{amount: amount, currency: DefaultCurrency}
+}
+
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount + other.amount, currency: m.currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount - other.amount, currency: m.currency}, nil
+}
+
+// Cmp คืน -1, 0 หรือ 1 เมื่อ m น้อยกว่า เท่ากับ หรือมากกว่า other
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.amount < other.amount:
+		return -1, nil
+	case m.amount > other.amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (m Money) Equal(other Money) bool {
+	return m.amount == other.amount && m.currency == other.currency
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%d %s", m.amount, m.currency)
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.currency != other.currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, other.currency)
+	}
+	return nil
+}
+
+type moneyJSON struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.amount, Currency: m.currency})
+}
+
+// UnmarshalJSON รับได้ทั้ง {"amount": 1000, "currency": "THB"} และตัวเลขอย่างเดียว (ถือเป็น DefaultCurrency)
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] != '{' {
+		var amount int64
+		if err := json.Unmarshal(data, &amount); err != nil {
+			return fmt.Errorf("invalid money amount: %w", err)
+		}
+		*m = NewDefaultMoney(amount)
+		return nil
+	}
+
+	var v moneyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	money, err := NewMoney(v.Amount, v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = money
+	return nil
+}
+
+// Value เก็บเฉพาะจำนวนเงิน คอลัมน์ในฐานข้อมูลเป็นสกุล DefaultCurrency เสมอ
+func (m Money) Value() (driver.Value, error) {
+	if m.currency != DefaultCurrency {
+		return nil, fmt.Errorf("%w: cannot store %s as %s", ErrCurrencyMismatch, m.currency, DefaultCurrency)
+	}
+	return m.amount, nil
+}
+
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		*m = NewDefaultMoney(v)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func mustMoney(t *testing.T, amount int64, currency Currency) Money {
+	t.Helper()
+	m, err := NewMoney(amount, currency)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestNewMoneyValidatesCurrency(t *testing.T) {
+	tests := []struct {
+		currency Currency
+		wantErr  error
+	}{
+		{currency: "THB"},
+		{currency: "USD"},
+		{currency: "thb", wantErr: ErrInvalidCurrency},
+		{currency: "BAHT", wantErr: ErrInvalidCurrency},
+		{currency: "", wantErr: ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.currency), func(t *testing.T) {
+			_, err := NewMoney(100, tt.currency)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	thb := NewDefaultMoney(100)
+	usd := mustMoney(t, 100, "USD")
+
+	tests := []struct {
+		name    string
+		op      func() (Money, error)
+		want    Money
+		wantErr error
+	}{
+		{name: "add", op: func() (Money, error) { return thb.Add(NewDefaultMoney(50)) }, want: NewDefaultMoney(150)},
+		{name: "sub", op: func() (Money, error) { return thb.Sub(NewDefaultMoney(30)) }, want: NewDefaultMoney(70)},
+		{name: "sub going negative", op: func() (Money, error) { return thb.Sub(NewDefaultMoney(250)) }, want: NewDefaultMoney(-150)},
+		{name: "add mismatched currencies", op: func() (Money, error) { return thb.Add(usd) }, wantErr: ErrCurrencyMismatch},
+		{name: "sub mismatched currencies", op: func() (Money, error) { return usd.Sub(thb) }, wantErr: ErrCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if got := NewDefaultMoney(-1); !got.IsNegative() || got.IsPositive() || got.IsZero() {
+		t.Fatalf("%s: IsNegative = %v, IsPositive = %v, IsZero = %v", got, got.IsNegative(), got.IsPositive(), got.IsZero())
+	}
+	if _, err := thb.Cmp(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("Cmp err = %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Money
+		wantErr bool
+	}{
+		{name: "object", data: `{"amount":1000,"currency":"USD"}`, want: Money{amount: 1000, currency: "USD"}},
+		{name: "bare amount uses default currency", data: `1000`, want: NewDefaultMoney(1000)},
+		{name: "negative amount", data: `{"amount":-5,"currency":"THB"}`, want: NewDefaultMoney(-5)},
+		{name: "invalid currency", data: `{"amount":1000,"currency":"baht"}`, wantErr: true},
+		{name: "fractional amount", data: `10.5`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("unmarshal %s = %s, want error", tt.data, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("unmarshal %s = %s, want %s", tt.data, got, tt.want)
+			}
+
+			// marshal แล้ว unmarshal กลับต้องได้ค่าเดิม
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var back Money
+			if err := json.Unmarshal(data, &back); err != nil {
+				t.Fatal(err)
+			}
+			if !back.Equal(got) {
+				t.Fatalf("round trip via %s = %s, want %s", data, back, got)
+			}
+		})
+	}
+}
+
+func TestMoneySQL(t *testing.T) {
+	v, err := NewDefaultMoney(1000).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != int64(1000) {
+		t.Fatalf("Value = %#v, want int64(1000)", v)
+	}
+
+	if _, err := mustMoney(t, 1000, "USD").Value(); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("Value of USD err = %v, want %v", err, ErrCurrencyMismatch)
+	}
+
+	tests := []struct {
+		name    string
+		src     any
+		want    Money
+		wantErr bool
+	}{
+		{name: "int64", src: int64(1000), want: NewDefaultMoney(1000)},
+		{name: "value round trip", src: v, want: NewDefaultMoney(1000)},
+		{name: "string", src: "1000", wantErr: true},
+		{name: "null", src: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := got.Scan(tt.src)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Scan(%#v) = %s, want error", tt.src, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("Scan(%#v) = %s, want %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
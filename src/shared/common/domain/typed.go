@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// TypedDomainEventHandler คือ handler ที่รับ domain event เป็น type จริง ไม่ต้อง type assert เอง
+type TypedDomainEventHandler[T DomainEvent] interface {
+	Handle(ctx context.Context, event T) error
+}
+
+// DomainEventHandlerFunc ทำให้ฟังก์ชันธรรมดาเป็น DomainEventHandler ได้
+type DomainEventHandlerFunc func(ctx context.Context, event DomainEvent) error
+
+func (f DomainEventHandlerFunc) Handle(ctx context.Context, event DomainEvent) error {
+	return f(ctx, event)
+}
+
+// EventTypeDeclarations บอก type ที่ aggregate raise ของแต่ละ event ให้ dispatcher ที่สร้างด้วย WithEventTypes ใช้ตรวจ handler
+type EventTypeDeclarations interface {
+	DeclaredType(name EventName) (reflect.Type, bool)
+}
+
+// typeBinder ผูก event name เข้ากับ type เพื่อตรวจว่า handler ทุกตัวของ event เดียวกันรับ type เดียวกัน
+type typeBinder interface {
+	bindType(eventType EventName, t reflect.Type) error
+}
+
+// RegisterTyped ลงทะเบียน TypedDomainEventHandler กับ dispatcher
+// ถ้า dispatcher ถูกสร้างด้วย WithEventTypes จะคืน error เมื่อ T ไม่ตรงกับ type ที่ประกาศไว้
+// ไม่เช่นนั้นจะคืน error ถ้ามี handler อื่นของ eventType เดียวกันลงทะเบียนไว้ด้วย type อื่น
+func RegisterTyped[T DomainEvent](d DomainEventDispatcher, eventType EventName, handler TypedDomainEventHandler[T], opts ...RegisterOption) error {
+	if b, ok := d.(typeBinder); ok {
+		if err := b.bindType(eventType, reflect.TypeFor[T]()); err != nil {
+			return err
+		}
+	}
+
+	d.Register(eventType, DomainEventHandlerFunc(func(ctx context.Context, event DomainEvent) error {
+		e, ok := event.(T)
+		if !ok {
+			var want T
+			return fmt.Errorf("%w: %s expected %T, got %T", ErrInvalidEvent, eventType, want, event)
+		}
+		return handler.Handle(ctx, e)
+	}), opts...)
+	return nil
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type orderPlaced struct {
+	BaseDomainEvent
+}
+
+type namedEvent interface {
+	DomainEvent
+	isNamed()
+}
+
+func (e *orderPlaced) isNamed() {}
+
+const orderPlacedName EventName = "OrderPlaced"
+
+type handlerOf[T DomainEvent] struct {
+	called int
+}
+
+func (h *handlerOf[T]) Handle(ctx context.Context, event T) error {
+	h.called++
+	return nil
+}
+
+// declaredTypes เป็น EventTypeDeclarations อย่างง่ายสำหรับ test
+type declaredTypes map[EventName]reflect.Type
+
+func (d declaredTypes) DeclaredType(name EventName) (reflect.Type, bool) {
+	t, ok := d[name]
+	return t, ok
+}
+
+func newDeclaredDispatcher(t *testing.T) DomainEventDispatcher {
+	t.Helper()
+	return NewSimpleDomainEventDispatcher(WithEventTypes(declaredTypes{
+		orderPlacedName: reflect.TypeFor[*orderPlaced](),
+	}))
+}
+
+func TestRegisterTypedChecksDeclaredType(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(d DomainEventDispatcher) error
+		wantErr  error
+	}{
+		{
+			name: "pointer handler matches pointer event",
+			register: func(d DomainEventDispatcher) error {
+				return RegisterTyped[*orderPlaced](d, orderPlacedName, &handlerOf[*orderPlaced]{})
+			},
+		},
+		{
+			name: "value handler for pointer event",
+			register: func(d DomainEventDispatcher) error {
+				return RegisterTyped[orderPlaced](d, orderPlacedName, &handlerOf[orderPlaced]{})
+			},
+			wantErr: ErrInvalidEvent,
+		},
+		{
+			name: "interface handler implemented by event",
+			register: func(d DomainEventDispatcher) error {
+				return RegisterTyped[namedEvent](d, orderPlacedName, &handlerOf[namedEvent]{})
+			},
+		},
+		{
+			name: "undeclared event",
+			register: func(d DomainEventDispatcher) error {
+				return RegisterTyped[*orderPlaced](d, "OrderShipped", &handlerOf[*orderPlaced]{})
+			},
+			wantErr: ErrInvalidEvent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.register(newDeclaredDispatcher(t))
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDispatchRejectsEventOfUndeclaredType(t *testing.T) {
+	d := newDeclaredDispatcher(t)
+	h := &handlerOf[*orderPlaced]{}
+	if err := RegisterTyped[*orderPlaced](d, orderPlacedName, h); err != nil {
+		t.Fatal(err)
+	}
+
+	valueEvent := orderPlaced{BaseDomainEvent{Name: orderPlacedName, At: time.Now()}}
+	if err := d.Dispatch(context.Background(), []DomainEvent{valueEvent}); !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("err = %v, want ErrInvalidEvent", err)
+	}
+
+	if err := d.Dispatch(context.Background(), []DomainEvent{&valueEvent}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.called != 1 {
+		t.Fatalf("handler called %d times, want 1", h.called)
+	}
+}
@@ -53,6 +53,10 @@ func ConflictError(message string, err ...error) *AppError {
 	return New(ErrConflict, message, err...)
 }
 
+func ConcurrencyConflictError(message string, err ...error) *AppError {
+	return New(ErrConcurrency, message, err...)
+}
+
 func BusinessRuleError(message string, err ...error) *AppError {
 	return New(ErrBusinessRule, message, err...)
 }
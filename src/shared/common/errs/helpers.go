@@ -45,7 +45,7 @@ func GetHTTPStatus(err error) int {
 		return fiber.StatusForbidden // 403
 	case ErrResourceNotFound:
 		return fiber.StatusNotFound // 404
-	case ErrConflict:
+	case ErrConflict, ErrConcurrency:
 		return fiber.StatusConflict // 409
 	case ErrBusinessRule:
 		return fiber.StatusBadRequest // 422
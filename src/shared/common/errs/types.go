@@ -8,6 +8,7 @@ const (
 	ErrAuthorization     ErrorType = "authorization_error"      // No permission to access resource
 	ErrResourceNotFound  ErrorType = "resource_not_found"       // Entity does not exist
 	ErrConflict          ErrorType = "conflict"                 // Conflict, already exists
+	ErrConcurrency       ErrorType = "concurrency_conflict"     // Data was modified by another request (version mismatch)
 	ErrBusinessRule      ErrorType = "business_rule_error"      // Business rule violation
 	ErrDataIntegrity     ErrorType = "data_integrity_error"     // Foreign key, constraint violations
 	ErrDatabaseFailure   ErrorType = "database_failure"         // Generic DB error
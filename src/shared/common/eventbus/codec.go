@@ -0,0 +1,238 @@
+package eventbus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Envelope คือข้อมูลที่ห่อ payload ของ event เวลาถูกบันทึกหรือส่งข้าม process
+type Envelope struct {
+	ID            string
+	Name          EventName
+	OccurredAt    time.Time
+	SchemaVersion int
+	Metadata      Metadata
+}
+
+// Codec แปลง event เป็น bytes พร้อม envelope และแปลงกลับ
+type Codec interface {
+	Encode(event Event, metadata Metadata) ([]byte, error)
+	Decode(data []byte) (Event, *Envelope, error)
+}
+
+// Format คือรูปแบบการ serialize ที่ Codec ใช้
+type Format interface {
+	encode(env Envelope, payload any) ([]byte, error)
+	decode(data []byte) (Envelope, func(v any) error, error)
+	convert(p Payload, v any) error
+}
+
+var (
+	// JSON ใช้กับตารางที่เก็บ payload เป็น jsonb
+	JSON Format = jsonFormat{}
+	// CBOR มีขนาดเล็กกว่า เหมาะกับการส่งผ่าน network
+	CBOR Format = newCBORFormat()
+)
+
+// DefaultCodec ใช้ DefaultRegistry และ JSON
+var DefaultCodec = NewCodec(DefaultRegistry, JSON)
+
+type codec struct {
+	registry *Registry
+	format   Format
+}
+
+func NewCodec(registry *Registry, format Format) Codec {
+	return &codec{
+		registry: registry,
+		format:   format,
+	}
+}
+
+func (c *codec) Encode(event Event, metadata Metadata) ([]byte, error) {
+	version, err := c.registry.Version(event.EventName())
+	if err != nil {
+		return nil, err
+	}
+
+	env := Envelope{
+		ID:            event.EventID(),
+		Name:          event.EventName(),
+		OccurredAt:    event.OccurredAt(),
+		SchemaVersion: version,
+		Metadata:      metadata,
+	}
+
+	data, err := c.format.encode(env, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event %s: %w", event.EventName(), err)
+	}
+	return data, nil
+}
+
+func (c *codec) Decode(data []byte) (Event, *Envelope, error) {
+	env, decodePayload, err := c.format.decode(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode event envelope: %w", err)
+	}
+
+	event, version, err := c.registry.New(env.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// envelope ที่บันทึกก่อนมี schema version ถือเป็น version 1
+	if env.SchemaVersion == 0 {
+		env.SchemaVersion = 1
+	}
+
+	switch {
+	case env.SchemaVersion == version:
+		if err := decodePayload(event); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode event %s: %w", env.Name, err)
+		}
+	case env.SchemaVersion < version:
+		var p Payload
+		if err := decodePayload(&p); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode event %s: %w", env.Name, err)
+		}
+		if p, err = c.registry.Upcast(env.Name, env.SchemaVersion, p); err != nil {
+			return nil, nil, err
+		}
+		if err := c.format.convert(p, event); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode upcasted event %s: %w", env.Name, err)
+		}
+		env.SchemaVersion = version
+	default:
+		// producer ใหม่กว่า consumer เช่นระหว่าง rolling deploy
+		return nil, nil, fmt.Errorf("%w: %s version %d (current version %d)", ErrUnknownSchemaVersion, env.Name, env.SchemaVersion, version)
+	}
+
+	// ข้อมูลใน envelope คือค่าที่ถูกต้องของ ID, Name และเวลา
+	if b, ok := event.(baseEventSetter); ok {
+		b.setBase(env.ID, env.Name, env.OccurredAt)
+	}
+
+	return event, &env, nil
+}
+
+type jsonEnvelope struct {
+	ID            string          `json:"id"`
+	Name          EventName       `json:"name"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	SchemaVersion int             `json:"schema_version"`
+	Metadata      Metadata        `json:"metadata,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+type jsonFormat struct{}
+
+func (jsonFormat) encode(env Envelope, payload any) ([]byte, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonEnvelope{
+		ID:            env.ID,
+		Name:          env.Name,
+		OccurredAt:    env.OccurredAt,
+		SchemaVersion: env.SchemaVersion,
+		Metadata:      env.Metadata,
+		Payload:       raw,
+	})
+}
+
+func (jsonFormat) decode(data []byte) (Envelope, func(v any) error, error) {
+	var wire jsonEnvelope
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return Envelope{}, nil, err
+	}
+	env := Envelope{
+		ID:            wire.ID,
+		Name:          wire.Name,
+		OccurredAt:    wire.OccurredAt,
+		SchemaVersion: wire.SchemaVersion,
+		Metadata:      wire.Metadata,
+	}
+	return env, func(v any) error {
+		// payload ที่ต้อง upcast ต้องเก็บตัวเลขเป็น json.Number ไม่เช่นนั้น int64 ที่เกิน 2^53 (เช่น ID) จะเพี้ยน
+		if _, ok := v.(*Payload); ok {
+			dec := json.NewDecoder(bytes.NewReader(wire.Payload))
+			dec.UseNumber()
+			return dec.Decode(v)
+		}
+		return json.Unmarshal(wire.Payload, v)
+	}, nil
+}
+
+func (jsonFormat) convert(p Payload, v any) error {
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+type cborEnvelope struct {
+	ID            string          `cbor:"id"`
+	Name          EventName       `cbor:"name"`
+	OccurredAt    time.Time       `cbor:"occurred_at"`
+	SchemaVersion int             `cbor:"schema_version"`
+	Metadata      Metadata        `cbor:"metadata,omitempty"`
+	Payload       cbor.RawMessage `cbor:"payload"`
+}
+
+type cborFormat struct {
+	em cbor.EncMode
+}
+
+func newCBORFormat() Format {
+	// ค่าเริ่มต้นของ cbor เก็บเวลาเป็นวินาที ต้องใช้ RFC3339Nano เพื่อไม่ให้เสียความละเอียด
+	em, err := cbor.EncOptions{Time: cbor.TimeRFC3339Nano}.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return cborFormat{em: em}
+}
+
+func (f cborFormat) encode(env Envelope, payload any) ([]byte, error) {
+	raw, err := f.em.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return f.em.Marshal(cborEnvelope{
+		ID:            env.ID,
+		Name:          env.Name,
+		OccurredAt:    env.OccurredAt,
+		SchemaVersion: env.SchemaVersion,
+		Metadata:      env.Metadata,
+		Payload:       raw,
+	})
+}
+
+func (cborFormat) decode(data []byte) (Envelope, func(v any) error, error) {
+	var wire cborEnvelope
+	if err := cbor.Unmarshal(data, &wire); err != nil {
+		return Envelope{}, nil, err
+	}
+	env := Envelope{
+		ID:            wire.ID,
+		Name:          wire.Name,
+		OccurredAt:    wire.OccurredAt,
+		SchemaVersion: wire.SchemaVersion,
+		Metadata:      wire.Metadata,
+	}
+	return env, func(v any) error { return cbor.Unmarshal(wire.Payload, v) }, nil
+}
+
+func (f cborFormat) convert(p Payload, v any) error {
+	raw, err := f.em.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return cbor.Unmarshal(raw, v)
+}
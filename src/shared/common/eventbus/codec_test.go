@@ -0,0 +1,52 @@
+package eventbus
+
+import (
+	"testing"
+	"time"
+)
+
+type accountOpened struct {
+	BaseEvent
+	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
+}
+
+const accountOpenedName EventName = "AccountOpened"
+
+// upcast ต้องไม่ทำให้ int64 ที่เกิน 2^53 เพี้ยน
+func TestCodecUpcastKeepsInt64Precision(t *testing.T) {
+	const id int64 = 1<<53 + 1
+
+	v1 := NewRegistry()
+	if err := v1.Register(accountOpenedName, 1, func() Event { return &accountOpened{} }); err != nil {
+		t.Fatal(err)
+	}
+	v2 := NewRegistry()
+	if err := v2.Register(accountOpenedName, 2, func() Event { return &accountOpened{} },
+		WithUpcaster(1, func(p Payload) (Payload, error) {
+			p["owner"] = "unknown"
+			return p, nil
+		}),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []Format{JSON, CBOR} {
+		data, err := NewCodec(v1, format).Encode(&accountOpened{
+			BaseEvent: BaseEvent{ID: "evt-1", Name: accountOpenedName, At: time.Now()},
+			AccountID: id,
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		event, env, err := NewCodec(v2, format).Decode(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := event.(*accountOpened)
+		if got.AccountID != id || got.Owner != "unknown" || env.SchemaVersion != 2 {
+			t.Fatalf("%T: decoded %+v (version %d), want account %d owned by unknown at version 2", format, got, env.SchemaVersion, id)
+		}
+	}
+}
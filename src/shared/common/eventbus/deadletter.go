@@ -0,0 +1,31 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrSubscriberNotFound = errors.New("subscriber not found")
+)
+
+// DeadLetter คือ event ที่ handler ประมวลผลไม่สำเร็จจนครบจำนวนครั้งที่กำหนด
+type DeadLetter struct {
+	ID          int64      `db:"id"`
+	EventID     string     `db:"event_id"`
+	EventName   EventName  `db:"event_name"`
+	HandlerName string     `db:"handler_name"`
+	Payload     []byte     `db:"payload"` // envelope ที่ encode ด้วย Codec
+	Error       string     `db:"error"`
+	Attempts    int        `db:"attempts"`
+	FailedAt    time.Time  `db:"failed_at"`
+	ReplayedAt  *time.Time `db:"replayed_at"`
+}
+
+type DeadLetterStore interface {
+	Save(ctx context.Context, dl *DeadLetter) error
+	List(ctx context.Context, limit int, offset int) ([]*DeadLetter, error)
+	FindByID(ctx context.Context, id int64) (*DeadLetter, error)
+	MarkReplayed(ctx context.Context, id int64) error
+}
@@ -0,0 +1,152 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log"
+	"sync"
+)
+
+var (
+	ErrQueueFull = errors.New("event bus queue is full")
+)
+
+// Backpressure กำหนดว่าจะทำอย่างไรเมื่อคิวของ worker เต็ม
+type Backpressure string
+
+const (
+	BackpressureBlock Backpressure = "block" // รอจนกว่าคิวจะว่าง หรือ context ของผู้ publish ถูก cancel
+	BackpressureDrop  Backpressure = "drop"  // ทิ้ง event แล้ว log ไว้
+	BackpressureError Backpressure = "error" // คืน ErrQueueFull ให้ผู้ publish
+)
+
+// PartitionKeyer ให้ event กำหนด key ของตัวเอง event ที่ key เดียวกันจะถูกส่งตามลำดับเสมอ
+type PartitionKeyer interface {
+	PartitionKey() string
+}
+
+// WorkerPoolConfig กำหนดการส่ง event ผ่าน worker จำนวนจำกัด
+// แต่ละ worker มีคิวของตัวเอง และ event ที่ partition key เดียวกันจะเข้า worker เดียวกันเสมอ
+type WorkerPoolConfig struct {
+	Workers      int
+	QueueSize    int
+	Backpressure Backpressure
+	PartitionKey func(Event) string // ถ้าไม่กำหนด ใช้ PartitionKeyer หรือ EventID ตามลำดับ
+}
+
+// WithWorkerPool เปลี่ยนจากการเปิด goroutine ใหม่ต่อ handler เป็น worker pool ที่จำกัดจำนวน
+func WithWorkerPool(cfg WorkerPoolConfig) Option {
+	return func(eb *InMemoryEventBus) {
+		eb.pool = newWorkerPool(cfg)
+	}
+}
+
+// WithSyncDelivery ให้ Publish เรียก handler ทุกตัวจนเสร็จก่อน return เหมาะกับการทดสอบ
+// เช่นเดียวกับโหมดอื่น error ของ handler ถูกจัดการด้วย retry policy และ dead-letter ไม่ถูกคืนจาก Publish
+func WithSyncDelivery() Option {
+	return func(eb *InMemoryEventBus) {
+		eb.sync = true
+	}
+}
+
+type job struct {
+	ctx   context.Context
+	sub   *subscription
+	event Event
+}
+
+type workerPool struct {
+	cfg    WorkerPoolConfig
+	queues []chan job
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+func newWorkerPool(cfg WorkerPoolConfig) *workerPool {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.Backpressure == "" {
+		cfg.Backpressure = BackpressureBlock
+	}
+	if cfg.PartitionKey == nil {
+		cfg.PartitionKey = defaultPartitionKey
+	}
+
+	p := &workerPool{
+		cfg:    cfg,
+		queues: make([]chan job, cfg.Workers),
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan job, cfg.QueueSize)
+	}
+	return p
+}
+
+func defaultPartitionKey(event Event) string {
+	if k, ok := event.(PartitionKeyer); ok {
+		return k.PartitionKey()
+	}
+	return event.EventID()
+}
+
+// start เปิด worker ทุกตัว โดย run คือฟังก์ชันที่ประมวลผล job หนึ่งงาน
+func (p *workerPool) start(run func(job)) {
+	for _, q := range p.queues {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for j := range q {
+				run(j)
+			}
+		}()
+	}
+}
+
+func (p *workerPool) submit(ctx context.Context, j job) error {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(p.cfg.PartitionKey(j.event)))
+	q := p.queues[h.Sum32()%uint32(len(p.queues))]
+
+	switch p.cfg.Backpressure {
+	case BackpressureDrop:
+		select {
+		case q <- j:
+			return nil
+		default:
+			log.Printf("event bus queue is full, dropped event %s (%s) for %s", j.event.EventName(), j.event.EventID(), j.sub.name)
+			return errDropped
+		}
+	case BackpressureError:
+		select {
+		case q <- j:
+			return nil
+		default:
+			return ErrQueueFull
+		}
+	default:
+		select {
+		case q <- j:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// errDropped บอก bus ว่า job ไม่ได้เข้าคิว แต่ไม่ต้องแจ้ง error ให้ผู้ publish
+var errDropped = errors.New("event dropped")
+
+// stop ปิดคิวทั้งหมดและรอ worker จบ ต้องเรียกหลังจากไม่มีการ submit แล้วเท่านั้น
+func (p *workerPool) stop() {
+	p.once.Do(func() {
+		for _, q := range p.queues {
+			close(q)
+		}
+		p.wg.Wait()
+	})
+}
@@ -0,0 +1,96 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	BaseEvent
+	Value string `json:"value"`
+}
+
+const testEventName EventName = "TestEvent"
+
+type memoryDeadLetters struct {
+	mu    sync.Mutex
+	saved []*DeadLetter
+}
+
+func (s *memoryDeadLetters) Save(ctx context.Context, dl *DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved = append(s.saved, dl)
+	return nil
+}
+
+func (s *memoryDeadLetters) List(ctx context.Context, limit int, offset int) ([]*DeadLetter, error) {
+	return s.saved, nil
+}
+
+func (s *memoryDeadLetters) FindByID(ctx context.Context, id int64) (*DeadLetter, error) {
+	return nil, nil
+}
+
+func (s *memoryDeadLetters) MarkReplayed(ctx context.Context, id int64) error {
+	return nil
+}
+
+func newTestBus(t *testing.T, deadLetters DeadLetterStore) *InMemoryEventBus {
+	t.Helper()
+	registry := NewRegistry()
+	if err := registry.Register(testEventName, 1, func() Event { return &testEvent{} }); err != nil {
+		t.Fatal(err)
+	}
+	return NewInMemoryEventBus(
+		WithSyncDelivery(),
+		WithCodec(NewCodec(registry, JSON)),
+		WithDeadLetterStore(deadLetters),
+	)
+}
+
+// subscriber ที่ล้มเหลวต้องไม่ทำให้ผู้ publish ส่ง event ซ้ำให้ subscriber ที่สำเร็จไปแล้ว
+func TestSyncPublishDoesNotReturnSubscriberErrors(t *testing.T) {
+	deadLetters := &memoryDeadLetters{}
+	bus := newTestBus(t, deadLetters)
+
+	var delivered int
+	bus.Subscribe(testEventName, HandlerFunc(func(ctx context.Context, event Event) error {
+		delivered++
+		return nil
+	}), WithHandlerName("ok"))
+	bus.Subscribe(testEventName, HandlerFunc(func(ctx context.Context, event Event) error {
+		return errors.New("boom")
+	}), WithHandlerName("failing"), WithRetry(RetryPolicy{MaxAttempts: 2}))
+
+	event := &testEvent{BaseEvent: BaseEvent{ID: "evt-1", Name: testEventName, At: time.Now()}, Value: "x"}
+	if err := bus.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish returned %v, want nil", err)
+	}
+
+	if delivered != 1 {
+		t.Fatalf("ok subscriber called %d times, want 1", delivered)
+	}
+	if len(deadLetters.saved) != 1 {
+		t.Fatalf("dead letters = %d, want 1", len(deadLetters.saved))
+	}
+	dl := deadLetters.saved[0]
+	if dl.HandlerName != "failing" || dl.Attempts != 2 {
+		t.Fatalf("dead letter = %s after %d attempt(s), want failing after 2", dl.HandlerName, dl.Attempts)
+	}
+}
+
+func TestSyncPublishAfterDrainReturnsBusClosed(t *testing.T) {
+	bus := newTestBus(t, &memoryDeadLetters{})
+	if _, err := bus.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	event := &testEvent{BaseEvent: BaseEvent{ID: "evt-1", Name: testEventName, At: time.Now()}}
+	if err := bus.Publish(context.Background(), event); !errors.Is(err, ErrBusClosed) {
+		t.Fatalf("Publish returned %v, want ErrBusClosed", err)
+	}
+}
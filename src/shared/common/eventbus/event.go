@@ -12,10 +12,11 @@ type Event interface {
 	OccurredAt() time.Time // เวลาที่ event เกิด
 }
 
+// ค่าใน BaseEvent ถูกเก็บไว้ใน Envelope จึงไม่ต้อง serialize ซ้ำใน payload
 type BaseEvent struct {
-	ID   string
-	Name EventName
-	At   time.Time
+	ID   string    `json:"-" cbor:"-"`
+	Name EventName `json:"-" cbor:"-"`
+	At   time.Time `json:"-" cbor:"-"`
 }
 
 func (e BaseEvent) EventID() string {
@@ -29,3 +30,13 @@ func (e BaseEvent) EventName() EventName {
 func (e BaseEvent) OccurredAt() time.Time {
 	return e.At
 }
+
+type baseEventSetter interface {
+	setBase(id string, name EventName, at time.Time)
+}
+
+func (e *BaseEvent) setBase(id string, name EventName, at time.Time) {
+	e.ID = id
+	e.Name = name
+	e.At = at
+}
@@ -2,6 +2,12 @@ package eventbus
 
 import (
 	"context"
+	"errors"
+)
+
+var (
+	ErrBusClosed             = errors.New("event bus is shutting down")
+	ErrDuplicateSubscription = errors.New("duplicate subscription name")
 )
 
 type IntegrationEventHandler interface {
@@ -10,5 +16,13 @@ type IntegrationEventHandler interface {
 
 type EventBus interface {
 	Publish(ctx context.Context, event Event) error
-	Subscribe(eventName EventName, handler IntegrationEventHandler)
+	// Subscribe รับได้ทั้งชื่อ event ตรงตัว และ pattern เช่น "Customer*" หรือ "*" (ตาม path.Match)
+	Subscribe(eventName EventName, handler IntegrationEventHandler, opts ...SubscribeOption) Subscription
+	// Subscriptions คืนชื่อ handler ที่ subscribe อยู่ แยกตามชื่อ event หรือ pattern
+	Subscriptions() map[EventName][]string
+	// Deliver ส่ง event ให้ subscriber ที่ชื่อ handlerName โดยตรงแบบ synchronous (ใช้ตอน replay)
+	Deliver(ctx context.Context, handlerName string, event Event) error
+	// Drain ปฏิเสธการ Publish ใหม่ด้วย ErrBusClosed และรอ handler ที่ยังทำงานอยู่ให้เสร็จ
+	// คืนจำนวน handler ที่ยังไม่เสร็จเมื่อ ctx หมดเวลา
+	Drain(ctx context.Context) (int, error)
 }
@@ -2,49 +2,320 @@ package eventbus
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"go-mma/shared/common/inflight"
 	"log"
+	"path"
+	"slices"
 	"sync"
+	"time"
 )
 
 // InMemoryEventBus is a simple event bus
 type InMemoryEventBus struct {
-	subscribers map[EventName][]IntegrationEventHandler
+	subscribers map[EventName][]*subscription
+	patterns    []*subscription // subscription แบบ wildcard ตรวจทุกครั้งที่ publish
 	mu          sync.RWMutex
+	deadLetters DeadLetterStore
+	codec       Codec
+	inflight    *inflight.Tracker
+	middlewares []Middleware
+	pool        *workerPool // nil คือเปิด goroutine ใหม่ต่อ handler
+	sync        bool
 }
 
+type Option func(*InMemoryEventBus)
+
 // NewInMemoryEventBus creates an event bus instance
-func NewInMemoryEventBus() *InMemoryEventBus {
-	return &InMemoryEventBus{
-		subscribers: make(map[EventName][]IntegrationEventHandler),
+func NewInMemoryEventBus(opts ...Option) *InMemoryEventBus {
+	eb := &InMemoryEventBus{
+		subscribers: make(map[EventName][]*subscription),
+		codec:       DefaultCodec,
+		inflight:    inflight.NewTracker(),
+	}
+
+	for _, opt := range opts {
+		opt(eb)
+	}
+
+	if eb.pool != nil {
+		eb.pool.start(func(j job) {
+			defer eb.inflight.Done()
+			eb.deliver(j.ctx, j.sub, j.event)
+		})
+	}
+
+	return eb
+}
+
+// WithDeadLetterStore เก็บ event ที่ handler ทำงานไม่สำเร็จหลัง retry ครบแล้ว
+func WithDeadLetterStore(store DeadLetterStore) Option {
+	return func(eb *InMemoryEventBus) {
+		eb.deadLetters = store
+	}
+}
+
+// WithCodec กำหนด Codec ที่ใช้ serialize event ก่อนบันทึกลง dead-letter store
+func WithCodec(codec Codec) Option {
+	return func(eb *InMemoryEventBus) {
+		eb.codec = codec
 	}
 }
 
-// Subscribe registers a handler for a specific event
-func (eb *InMemoryEventBus) Subscribe(eventName EventName, handler IntegrationEventHandler) {
+// WithMiddleware เพิ่ม middleware ที่ครอบทุก subscription ของ event bus นี้
+func WithMiddleware(mws ...Middleware) Option {
+	return func(eb *InMemoryEventBus) {
+		eb.middlewares = append(eb.middlewares, mws...)
+	}
+}
+
+// Subscribe registers a handler for a specific event or pattern
+// pattern ที่ผิดรูปแบบ หรือชื่อ subscription ซ้ำ ถือเป็นความผิดพลาดของโปรแกรม จึง panic ตั้งแต่ตอนลงทะเบียน
+func (eb *InMemoryEventBus) Subscribe(eventName EventName, handler IntegrationEventHandler, opts ...SubscribeOption) Subscription {
+	if _, err := path.Match(string(eventName), ""); err != nil {
+		panic(fmt.Errorf("invalid subscription pattern %q: %w", eventName, err))
+	}
+
+	s := newSubscription(eventName, handler, eb.middlewares, opts...)
+
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 
-	eb.subscribers[eventName] = append(eb.subscribers[eventName], handler)
+	if err := eb.checkName(s); err != nil {
+		panic(err)
+	}
+
+	if isPattern(eventName) {
+		eb.patterns = append(eb.patterns, s)
+	} else {
+		eb.subscribers[eventName] = append(eb.subscribers[eventName], s)
+	}
+
+	return UnsubscribeFunc(func() {
+		eb.unsubscribe(s)
+	})
 }
 
-// Publish sends an event to all subscribers
-func (eb *InMemoryEventBus) Publish(ctx context.Context, event Event) error {
+// checkName ตรวจว่าไม่มี subscription อื่นที่ชื่อเดียวกันและอาจได้รับ event เดียวกัน
+// เพราะ Deliver (replay จาก dead-letter) เลือก subscriber ด้วยชื่อ
+// ชื่อเดียวกันใช้ซ้ำได้เฉพาะกับชื่อ event ตรงตัวที่ต่างกัน เช่น SubscribeMany ต้องถือ lock ก่อนเรียก
+func (eb *InMemoryEventBus) checkName(s *subscription) error {
+	conflict := func(other *subscription) bool {
+		if other.name != s.name {
+			return false
+		}
+		return other.pattern == s.pattern || isPattern(other.pattern) || isPattern(s.pattern)
+	}
+
+	for _, subs := range eb.subscribers {
+		for _, other := range subs {
+			if conflict(other) {
+				return fmt.Errorf("%w: %q already subscribes to %s, use WithHandlerName to give %s a unique name", ErrDuplicateSubscription, s.name, other.pattern, s.pattern)
+			}
+		}
+	}
+	for _, other := range eb.patterns {
+		if conflict(other) {
+			return fmt.Errorf("%w: %q already subscribes to %s, use WithHandlerName to give %s a unique name", ErrDuplicateSubscription, s.name, other.pattern, s.pattern)
+		}
+	}
+	return nil
+}
+
+func (eb *InMemoryEventBus) unsubscribe(s *subscription) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	remove := func(subs []*subscription) []*subscription {
+		return slices.DeleteFunc(slices.Clone(subs), func(sub *subscription) bool { return sub == s })
+	}
+
+	if isPattern(s.pattern) {
+		eb.patterns = remove(eb.patterns)
+		return
+	}
+	eb.subscribers[s.pattern] = remove(eb.subscribers[s.pattern])
+	if len(eb.subscribers[s.pattern]) == 0 {
+		delete(eb.subscribers, s.pattern)
+	}
+}
+
+// Subscriptions คืนชื่อ handler แยกตามชื่อ event หรือ pattern ที่ใช้ subscribe
+func (eb *InMemoryEventBus) Subscriptions() map[EventName][]string {
 	eb.mu.RLock()
 	defer eb.mu.RUnlock()
 
-	handlers, ok := eb.subscribers[event.EventName()]
-	if !ok {
-		return nil
+	result := make(map[EventName][]string, len(eb.subscribers))
+	for name, subs := range eb.subscribers {
+		for _, s := range subs {
+			result[name] = append(result[name], s.name)
+		}
 	}
+	for _, s := range eb.patterns {
+		result[s.pattern] = append(result[s.pattern], s.name)
+	}
+	return result
+}
+
+// match คืน subscription ทั้งหมดที่ต้องได้รับ event นี้ ต้องถือ lock ก่อนเรียก
+func (eb *InMemoryEventBus) match(eventName EventName) []*subscription {
+	subs := append([]*subscription(nil), eb.subscribers[eventName]...)
+	for _, s := range eb.patterns {
+		if matchPattern(s.pattern, eventName) {
+			subs = append(subs, s)
+		}
+	}
+	return subs
+}
+
+// Publish sends an event to all subscribers
+func (eb *InMemoryEventBus) Publish(ctx context.Context, event Event) error {
+	if eb.inflight.Closed() {
+		return ErrBusClosed
+	}
+
+	// copy slice ออกมาก่อน เพื่อไม่ให้ถือ lock ไว้ระหว่างรอคิว
+	eb.mu.RLock()
+	subs := eb.match(event.EventName())
+	eb.mu.RUnlock()
 
 	busCtx := context.WithValue(ctx, "name", "context in event bus")
-	for _, handler := range handlers {
-		go func(h IntegrationEventHandler) {
-			err := h.Handle(busCtx, event)
-			if err != nil {
-				log.Printf("error handling event %s: %v", event.EventName(), err)
+
+	var errs error
+	for _, sub := range subs {
+		err := eb.dispatch(busCtx, sub, event)
+		if errors.Is(err, inflight.ErrClosed) {
+			return ErrBusClosed
+		}
+		errs = errors.Join(errs, err)
+	}
+	return errs
+}
+
+func (eb *InMemoryEventBus) dispatch(ctx context.Context, sub *subscription, event Event) error {
+	switch {
+	case eb.sync:
+		if err := eb.inflight.Add(); err != nil {
+			return err
+		}
+		defer eb.inflight.Done()
+		eb.deliver(ctx, sub, event)
+		return nil
+
+	case eb.pool != nil:
+		if err := eb.inflight.Add(); err != nil {
+			return err
+		}
+		// handler ทำงานทีหลังใน worker จึงไม่ผูกกับการ cancel ของผู้ publish
+		j := job{ctx: context.WithoutCancel(ctx), sub: sub, event: event}
+		if err := eb.pool.submit(ctx, j); err != nil {
+			eb.inflight.Done()
+			if errors.Is(err, errDropped) {
+				return nil
 			}
-		}(handler)
+			return err
+		}
+		return nil
+
+	default:
+		return eb.inflight.Go(func() {
+			eb.deliver(ctx, sub, event)
+		})
+	}
+}
+
+// deliver เรียก handler ตาม retry policy และส่งเข้า dead-letter เมื่อไม่สำเร็จ
+// error ของ handler ไม่ถูกคืนให้ผู้ publish เพราะผู้ publish (เช่น outbox relay) จะส่ง event ซ้ำให้ทุก subscriber
+// รวมถึงตัวที่ทำงานสำเร็จไปแล้ว การลองใหม่เฉพาะ subscriber ที่ล้มเหลวทำผ่าน retry policy และ dead-letter แทน
+func (eb *InMemoryEventBus) deliver(ctx context.Context, sub *subscription, event Event) {
+	attempts, err := eb.handleWithRetry(ctx, sub, event)
+	if err != nil {
+		log.Printf("error handling event %s by %s after %d attempt(s): %v", event.EventName(), sub.name, attempts, err)
+		eb.deadLetter(ctx, sub, event, attempts, err)
+	}
+}
+
+// Closed บอกว่า Drain ถูกเรียกไปแล้วหรือยัง
+func (eb *InMemoryEventBus) Closed() bool {
+	return eb.inflight.Closed()
+}
+
+// Drain ปฏิเสธ event ใหม่ และรอ handler ที่ยังทำงานอยู่ (รวมถึงที่กำลังรอ retry) ให้เสร็จ
+func (eb *InMemoryEventBus) Drain(ctx context.Context) (int, error) {
+	abandoned, err := eb.inflight.Drain(ctx)
+	if err == nil && eb.pool != nil {
+		eb.pool.stop()
+	}
+	return abandoned, err
+}
+
+// Deliver ส่ง event ให้ subscriber ที่ระบุชื่อโดยตรง ไม่มี retry และไม่ส่งเข้า dead-letter
+func (eb *InMemoryEventBus) Deliver(ctx context.Context, handlerName string, event Event) error {
+	eb.mu.RLock()
+	var target *subscription
+	for _, sub := range eb.match(event.EventName()) {
+		if sub.name == handlerName {
+			target = sub
+			break
+		}
+	}
+	eb.mu.RUnlock()
+
+	if target == nil {
+		return fmt.Errorf("%w: %s for event %s", ErrSubscriberNotFound, handlerName, event.EventName())
+	}
+
+	return target.Handle(ctx, event)
+}
+
+func (eb *InMemoryEventBus) handleWithRetry(ctx context.Context, s *subscription, event Event) (int, error) {
+	maxAttempts := s.retry.attempts()
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = s.Handle(ctx, event); err == nil {
+			return attempt, nil
+		}
+
+		if attempt == maxAttempts {
+			return attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, fmt.Errorf("retry aborted: %w (last error: %v)", ctx.Err(), err)
+		case <-time.After(s.retry.Backoff(attempt)):
+		}
+	}
+	return maxAttempts, err
+}
+
+func (eb *InMemoryEventBus) deadLetter(ctx context.Context, s *subscription, event Event, attempts int, cause error) {
+	if eb.deadLetters == nil {
+		return
+	}
+
+	payload, err := eb.codec.Encode(event, MetadataFromContext(ctx))
+	if err != nil {
+		log.Printf("error marshaling dead-letter event %s: %v", event.EventName(), err)
+		return
+	}
+
+	dl := &DeadLetter{
+		EventID:     event.EventID(),
+		EventName:   event.EventName(),
+		HandlerName: s.name,
+		Payload:     payload,
+		Error:       cause.Error(),
+		Attempts:    attempts,
+	}
+
+	// ใช้ context ใหม่ เพราะ context เดิมอาจถูก cancel ไปแล้ว
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	if err := eb.deadLetters.Save(saveCtx, dl); err != nil {
+		log.Printf("error saving dead-letter event %s: %v", event.EventName(), err)
 	}
-	return nil
 }
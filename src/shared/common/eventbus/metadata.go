@@ -0,0 +1,23 @@
+package eventbus
+
+import "context"
+
+// Metadata คือข้อมูลประกอบของ event เช่น request id หรือ trace id
+type Metadata map[string]string
+
+const (
+	MetadataTraceID = "trace_id"
+)
+
+type metadataKey struct{}
+
+// ContextWithMetadata แนบ metadata ไปกับ context เพื่อให้ถูกบันทึกลง envelope ตอน encode
+func ContextWithMetadata(ctx context.Context, md Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, md)
+}
+
+// MetadataFromContext คืน metadata ที่แนบมากับ context (อาจเป็น nil)
+func MetadataFromContext(ctx context.Context) Metadata {
+	md, _ := ctx.Value(metadataKey{}).(Metadata)
+	return md
+}
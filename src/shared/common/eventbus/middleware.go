@@ -0,0 +1,131 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-mma/shared/common/logger"
+	"runtime/debug"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrHandlerPanic = errors.New("event handler panic")
+)
+
+// Middleware ห่อ IntegrationEventHandler เพื่อเพิ่มพฤติกรรมร่วม เช่น logging หรือ timeout
+type Middleware func(next IntegrationEventHandler) IntegrationEventHandler
+
+// Chain ห่อ handler ด้วย middleware ตามลำดับ ตัวแรกจะอยู่นอกสุด
+func Chain(handler IntegrationEventHandler, mws ...Middleware) IntegrationEventHandler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](handler)
+	}
+	return handler
+}
+
+type handlerNameKey struct{}
+
+// HandlerNameFromContext คืนชื่อ subscription ที่กำลังประมวลผล event อยู่
+func HandlerNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(handlerNameKey{}).(string)
+	return name
+}
+
+func contextWithHandlerName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, handlerNameKey{}, name)
+}
+
+// Recovery แปลง panic ใน handler เป็น error แทนที่จะทำให้ทั้ง process ล่ม
+func Recovery() Middleware {
+	return func(next IntegrationEventHandler) IntegrationEventHandler {
+		return HandlerFunc(func(ctx context.Context, event Event) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Log.Error("event handler panic",
+						zap.String("handler", HandlerNameFromContext(ctx)),
+						zap.String("eventId", event.EventID()),
+						zap.Any("panic", r),
+						zap.ByteString("stack", debug.Stack()),
+					)
+					err = fmt.Errorf("%w: %v", ErrHandlerPanic, r)
+				}
+			}()
+			return next.Handle(ctx, event)
+		})
+	}
+}
+
+// Logging บันทึก log ทุกครั้งที่ handler ทำงาน พร้อม event id และเวลาที่ใช้
+func Logging() Middleware {
+	return func(next IntegrationEventHandler) IntegrationEventHandler {
+		return HandlerFunc(func(ctx context.Context, event Event) error {
+			start := time.Now()
+			err := next.Handle(ctx, event)
+
+			fields := []zap.Field{
+				zap.String("handler", HandlerNameFromContext(ctx)),
+				zap.String("eventId", event.EventID()),
+				zap.String("eventName", string(event.EventName())),
+				zap.String("traceId", MetadataFromContext(ctx)[MetadataTraceID]),
+				zap.Duration("latency", time.Since(start)),
+			}
+			if err != nil {
+				logger.Log.Error("event handled with error", append(fields, zap.Error(err))...)
+				return err
+			}
+			logger.Log.Info("event handled", fields...)
+			return nil
+		})
+	}
+}
+
+// Timeout ยกเลิก context ของ handler เมื่อทำงานนานเกิน d
+func Timeout(d time.Duration) Middleware {
+	return func(next IntegrationEventHandler) IntegrationEventHandler {
+		return HandlerFunc(func(ctx context.Context, event Event) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next.Handle(ctx, event)
+		})
+	}
+}
+
+// MetricsRecorder รับค่าเวลาที่ handler ใช้ เพื่อส่งต่อให้ระบบ metrics ที่ใช้อยู่
+type MetricsRecorder interface {
+	ObserveHandler(handlerName string, eventName EventName, duration time.Duration, err error)
+}
+
+// Metrics วัดเวลาที่ handler ใช้ในแต่ละครั้งแล้วส่งให้ recorder
+func Metrics(recorder MetricsRecorder) Middleware {
+	return func(next IntegrationEventHandler) IntegrationEventHandler {
+		return HandlerFunc(func(ctx context.Context, event Event) error {
+			start := time.Now()
+			err := next.Handle(ctx, event)
+			recorder.ObserveHandler(HandlerNameFromContext(ctx), event.EventName(), time.Since(start), err)
+			return err
+		})
+	}
+}
+
+// Tracing ทำให้ทุก event มี trace id ใน metadata ของ context
+// ถ้า event ต้นทางไม่มี trace id จะใช้ event id แทน
+// event ที่ handler publish ต่อจะได้ trace id เดียวกันผ่าน MetadataFromContext
+func Tracing() Middleware {
+	return func(next IntegrationEventHandler) IntegrationEventHandler {
+		return HandlerFunc(func(ctx context.Context, event Event) error {
+			md := MetadataFromContext(ctx)
+			if md[MetadataTraceID] == "" {
+				traced := make(Metadata, len(md)+1)
+				for k, v := range md {
+					traced[k] = v
+				}
+				traced[MetadataTraceID] = event.EventID()
+				ctx = ContextWithMetadata(ctx, traced)
+			}
+			return next.Handle(ctx, event)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package eventbus
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrEventNotRegistered = errors.New("event type is not registered")
+	ErrEventRegistered    = errors.New("event type is already registered")
+)
+
+// EventFactory คืนค่า pointer ของ event เปล่า เพื่อใช้ decode payload กลับมา
+type EventFactory func() Event
+
+type eventType struct {
+	version   int
+	factory   EventFactory
+	upcasters map[int]Upcaster
+	schemas   map[int][]string
+}
+
+// Registry จับคู่ EventName กับ Go type และ schema version ปัจจุบันของ event นั้น
+type Registry struct {
+	types map[EventName]eventType
+	mu    sync.RWMutex
+}
+
+// DefaultRegistry ใช้ร่วมกันทั้ง application โดย event ใน shared/messaging จะ register ตัวเองใน init()
+var DefaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{
+		types: make(map[EventName]eventType),
+	}
+}
+
+// Register ผูก event name เข้ากับ factory และ schema version (เริ่มที่ 1)
+func (r *Registry) Register(name EventName, version int, factory EventFactory, opts ...RegisterOption) error {
+	if version < 1 {
+		return fmt.Errorf("invalid schema version %d for event %s", version, name)
+	}
+
+	t := eventType{
+		version:   version,
+		factory:   factory,
+		upcasters: make(map[int]Upcaster),
+		schemas:   make(map[int][]string),
+	}
+	for _, opt := range opts {
+		opt(&t)
+	}
+	if err := t.verify(name); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.types[name]; ok {
+		return fmt.Errorf("%w: %s", ErrEventRegistered, name)
+	}
+	r.types[name] = t
+	return nil
+}
+
+// New สร้าง event เปล่าของ name ที่ลงทะเบียนไว้ พร้อม schema version ปัจจุบัน
+func (r *Registry) New(name EventName) (Event, int, error) {
+	r.mu.RLock()
+	t, ok := r.types[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %s", ErrEventNotRegistered, name)
+	}
+	return t.factory(), t.version, nil
+}
+
+// Version คืน schema version ปัจจุบันของ event name
+func (r *Registry) Version(name EventName) (int, error) {
+	r.mu.RLock()
+	t, ok := r.types[name]
+	r.mu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrEventNotRegistered, name)
+	}
+	return t.version, nil
+}
+
+// Upcast แปลง payload ที่ถูกบันทึกด้วย schema version เก่าให้เป็น version ปัจจุบัน
+func (r *Registry) Upcast(name EventName, version int, p Payload) (Payload, error) {
+	r.mu.RLock()
+	t, ok := r.types[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrEventNotRegistered, name)
+	}
+	if version < 1 || version > t.version {
+		return nil, fmt.Errorf("%w: %s version %d (current version %d)", ErrUnknownSchemaVersion, name, version, t.version)
+	}
+	return t.upcast(name, version, p)
+}
+
+// MustRegister ลงทะเบียน event ใน DefaultRegistry และ panic ถ้าผิดพลาด เหมาะกับการเรียกใน init()
+func MustRegister(name EventName, version int, factory EventFactory, opts ...RegisterOption) {
+	if err := DefaultRegistry.Register(name, version, factory, opts...); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,17 @@
+package eventbus
+
+import "context"
+
+type replayKey struct{}
+
+// ContextWithReplay บอก handler ว่า event นี้ถูกส่งซ้ำโดยตั้งใจ (เช่น replay จาก event log)
+// handler ที่กัน event ซ้ำ เช่น inbox จะประมวลผลอีกครั้งแม้จะเคยเห็น event นี้แล้ว
+func ContextWithReplay(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replayKey{}, true)
+}
+
+// IsReplay คืนค่า true ถ้า event ถูกส่งมาจากการ replay
+func IsReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayKey{}).(bool)
+	return replay
+}
@@ -0,0 +1,60 @@
+package eventbus
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy กำหนดการลองส่ง event ซ้ำเมื่อ handler คืน error
+type RetryPolicy struct {
+	MaxAttempts    int           // จำนวนครั้งทั้งหมดรวมครั้งแรก (ค่า <= 1 คือไม่ retry)
+	InitialBackoff time.Duration // เวลารอก่อน retry ครั้งแรก
+	MaxBackoff     time.Duration // เวลารอสูงสุดต่อครั้ง
+	Multiplier     float64       // ตัวคูณของ exponential backoff
+	Jitter         float64       // สัดส่วนการสุ่มเวลารอ 0.0 - 1.0
+}
+
+// NoRetry คือ policy เริ่มต้น ส่งครั้งเดียวแล้วจบ
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// DefaultRetryPolicy ลอง 5 ครั้ง เริ่มรอ 200ms และเพิ่มเป็น 2 เท่าทุกครั้ง ไม่เกิน 10s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// Backoff คืนเวลาที่ต้องรอก่อนส่งครั้งที่ attempt+1 (attempt เริ่มที่ 1)
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if p.InitialBackoff <= 0 {
+		return 0
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		// สุ่มในช่วง ±jitter เพื่อไม่ให้ทุก handler retry พร้อมกัน
+		backoff = backoff * (1 - jitter + rand.Float64()*2*jitter)
+	}
+
+	return time.Duration(backoff)
+}
+
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
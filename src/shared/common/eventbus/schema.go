@@ -0,0 +1,130 @@
+package eventbus
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrUnknownSchemaVersion = errors.New("unknown event schema version")
+	ErrSchemaMismatch       = errors.New("event struct does not match its declared schema")
+)
+
+// Payload คือ payload ของ event ที่ decode แบบไม่ระบุ type เพื่อให้ upcaster แก้ไขได้
+// ตัวเลขเป็น json.Number (JSON) หรือ int64/uint64 (CBOR) ขึ้นกับ Format
+type Payload map[string]any
+
+// Upcaster แปลง payload จาก schema version หนึ่งไปเป็น version ถัดไป
+type Upcaster func(p Payload) (Payload, error)
+
+type RegisterOption func(*eventType)
+
+// WithUpcaster กำหนด upcaster ที่แปลง payload จาก fromVersion ไปเป็น fromVersion+1
+// event ที่ version มากกว่า 1 ต้องมี upcaster ครบทุกขั้น
+func WithUpcaster(fromVersion int, fn Upcaster) RegisterOption {
+	return func(t *eventType) {
+		t.upcasters[fromVersion] = fn
+	}
+}
+
+// WithSchema บันทึกชื่อ field (ตาม json tag) ของ schema version นั้นไว้เป็นสัญญากับผู้รับ
+// schema ของ version ปัจจุบันต้องตรงกับ struct เสมอ ถ้าลบหรือเพิ่ม field โดยไม่ขึ้น version ใหม่
+// การ register จะล้มเหลว (MustRegister จะ panic ตอนเริ่มโปรแกรม)
+func WithSchema(version int, fields ...string) RegisterOption {
+	return func(t *eventType) {
+		t.schemas[version] = fields
+	}
+}
+
+func (t eventType) verify(name EventName) error {
+	for v := 1; v < t.version; v++ {
+		if _, ok := t.upcasters[v]; !ok {
+			return fmt.Errorf("missing upcaster from version %d to %d for event %s", v, v+1, name)
+		}
+	}
+	for v := range t.upcasters {
+		if v < 1 || v >= t.version {
+			return fmt.Errorf("upcaster from version %d is out of range for event %s (current version %d)", v, name, t.version)
+		}
+	}
+	for v := range t.schemas {
+		if v < 1 || v > t.version {
+			return fmt.Errorf("schema version %d is out of range for event %s (current version %d)", v, name, t.version)
+		}
+	}
+
+	declared, ok := t.schemas[t.version]
+	if !ok {
+		return nil
+	}
+
+	actual := payloadFields(reflect.TypeOf(t.factory()))
+	var missing, undeclared []string
+	for _, f := range declared {
+		if !slices.Contains(actual, f) {
+			missing = append(missing, f)
+		}
+	}
+	for _, f := range actual {
+		if !slices.Contains(declared, f) {
+			undeclared = append(undeclared, f)
+		}
+	}
+	if len(missing) > 0 || len(undeclared) > 0 {
+		return fmt.Errorf("%w: %s version %d (missing: [%s], undeclared: [%s]); bump the version and add an upcaster",
+			ErrSchemaMismatch, name, t.version, strings.Join(missing, ", "), strings.Join(undeclared, ", "))
+	}
+	return nil
+}
+
+// upcast แปลง payload จาก version ไปจนถึง version ปัจจุบัน
+func (t eventType) upcast(name EventName, version int, p Payload) (Payload, error) {
+	for v := version; v < t.version; v++ {
+		next, err := t.upcasters[v](p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upcast event %s from version %d: %w", name, v, err)
+		}
+		p = next
+	}
+	return p, nil
+}
+
+// PayloadFields คืนชื่อ field ของ payload ของ event ตาม json tag ใช้เทียบกับ schema ที่บันทึกไว้ใน test
+func PayloadFields(event Event) []string {
+	return payloadFields(reflect.TypeOf(event))
+}
+
+// payloadFields คืนชื่อ field ที่ถูก serialize ตาม json tag (ซึ่ง CBOR ก็ใช้เช่นกัน)
+func payloadFields(t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			fields = append(fields, payloadFields(f.Type)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
@@ -0,0 +1,106 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Subscription คือ handle ของการ subscribe ใช้ยกเลิกการรับ event
+type Subscription interface {
+	// Unsubscribe หยุดส่ง event ใหม่ให้ handler เรียกซ้ำได้
+	// event ที่เข้าคิวไปแล้วก่อนยกเลิกยังอาจถูกส่งให้ handler
+	Unsubscribe()
+}
+
+// UnsubscribeFunc ทำให้ฟังก์ชันธรรมดาเป็น Subscription ได้
+type UnsubscribeFunc func()
+
+func (f UnsubscribeFunc) Unsubscribe() {
+	f()
+}
+
+type subscriptions []Subscription
+
+func (s subscriptions) Unsubscribe() {
+	for _, sub := range s {
+		sub.Unsubscribe()
+	}
+}
+
+// SubscribeMany ลงทะเบียน handler ตัวเดียวกับหลาย event และคืน Subscription ที่ยกเลิกได้ทั้งหมดในครั้งเดียว
+func SubscribeMany(bus EventBus, eventNames []EventName, handler IntegrationEventHandler, opts ...SubscribeOption) Subscription {
+	subs := make(subscriptions, 0, len(eventNames))
+	for _, name := range eventNames {
+		subs = append(subs, bus.Subscribe(name, handler, opts...))
+	}
+	return subs
+}
+
+// isPattern บอกว่า eventName มีอักขระพิเศษของ path.Match หรือไม่
+func isPattern(eventName EventName) bool {
+	return strings.ContainsAny(string(eventName), `*?[\`)
+}
+
+// matchPattern ตรวจ pattern กับชื่อ event ถ้า pattern ผิดรูปแบบจะถือว่าไม่ตรง
+func matchPattern(pattern EventName, eventName EventName) bool {
+	ok, err := path.Match(string(pattern), string(eventName))
+	return err == nil && ok
+}
+
+type subscription struct {
+	pattern     EventName
+	name        string
+	retry       RetryPolicy
+	middlewares []Middleware
+	pipeline    IntegrationEventHandler // handler ที่ห่อด้วย middleware แล้ว
+}
+
+type SubscribeOption func(*subscription)
+
+// newSubscription สร้าง subscription โดย global middleware จะอยู่นอก middleware ของ subscription
+func newSubscription(pattern EventName, handler IntegrationEventHandler, global []Middleware, opts ...SubscribeOption) *subscription {
+	s := &subscription{
+		pattern: pattern,
+		name:    fmt.Sprintf("%T", handler),
+		retry:   NoRetry,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mws := append(append([]Middleware(nil), global...), s.middlewares...)
+	s.pipeline = Chain(handler, mws...)
+
+	return s
+}
+
+// Handle เรียก pipeline พร้อมแนบชื่อ subscription ไว้ใน context ให้ middleware ใช้
+func (s *subscription) Handle(ctx context.Context, event Event) error {
+	return s.pipeline.Handle(contextWithHandlerName(ctx, s.name), event)
+}
+
+// WithHandlerName ตั้งชื่อ subscription ใช้อ้างอิงตอน replay จาก dead-letter
+// ถ้าไม่ระบุจะใช้ชื่อ type ของ handler ซึ่งซ้ำกันได้ง่าย (เช่น HandlerFunc หรือ handler ที่ห่อด้วย inbox)
+// จึงควรระบุเสมอ Subscribe จะ panic ถ้าชื่อซ้ำกับ subscription อื่นที่อาจได้รับ event เดียวกัน
+func WithHandlerName(name string) SubscribeOption {
+	return func(s *subscription) {
+		s.name = name
+	}
+}
+
+// WithRetry กำหนด retry policy ให้กับ subscription นี้
+func WithRetry(policy RetryPolicy) SubscribeOption {
+	return func(s *subscription) {
+		s.retry = policy
+	}
+}
+
+// WithSubscriptionMiddleware เพิ่ม middleware เฉพาะ subscription นี้ (ทำงานหลัง global middleware)
+func WithSubscriptionMiddleware(mws ...Middleware) SubscribeOption {
+	return func(s *subscription) {
+		s.middlewares = append(s.middlewares, mws...)
+	}
+}
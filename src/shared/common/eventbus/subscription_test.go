@@ -0,0 +1,80 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSubscribeRejectsDuplicateNames(t *testing.T) {
+	noop := HandlerFunc(func(ctx context.Context, event Event) error { return nil })
+
+	tests := []struct {
+		name      string
+		first     EventName
+		firstOpt  []SubscribeOption
+		second    EventName
+		secondOpt []SubscribeOption
+		wantPanic bool
+	}{
+		{
+			name:      "default name on same event",
+			first:     "CustomerCreated",
+			second:    "CustomerCreated",
+			wantPanic: true,
+		},
+		{
+			name:      "explicit names on same event",
+			first:     "CustomerCreated",
+			firstOpt:  []SubscribeOption{WithHandlerName("welcome-email")},
+			second:    "CustomerCreated",
+			secondOpt: []SubscribeOption{WithHandlerName("audit")},
+		},
+		{
+			name:      "same name on different events",
+			first:     "CustomerCreated",
+			firstOpt:  []SubscribeOption{WithHandlerName("audit")},
+			second:    "OrderCreated",
+			secondOpt: []SubscribeOption{WithHandlerName("audit")},
+		},
+		{
+			name:      "same name on pattern and event",
+			first:     "Customer*",
+			firstOpt:  []SubscribeOption{WithHandlerName("audit")},
+			second:    "OrderCreated",
+			secondOpt: []SubscribeOption{WithHandlerName("audit")},
+			wantPanic: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewInMemoryEventBus()
+			bus.Subscribe(tt.first, noop, tt.firstOpt...)
+
+			defer func() {
+				r := recover()
+				if !tt.wantPanic {
+					if r != nil {
+						t.Fatalf("unexpected panic: %v", r)
+					}
+					return
+				}
+				err, ok := r.(error)
+				if !ok || !errors.Is(err, ErrDuplicateSubscription) {
+					t.Fatalf("panic = %v, want ErrDuplicateSubscription", r)
+				}
+			}()
+			bus.Subscribe(tt.second, noop, tt.secondOpt...)
+		})
+	}
+}
+
+func TestSubscribeAllowsNameAfterUnsubscribe(t *testing.T) {
+	noop := HandlerFunc(func(ctx context.Context, event Event) error { return nil })
+	bus := NewInMemoryEventBus()
+
+	sub := bus.Subscribe("CustomerCreated", noop, WithHandlerName("audit"))
+	sub.Unsubscribe()
+	bus.Subscribe("CustomerCreated", noop, WithHandlerName("audit"))
+}
@@ -0,0 +1,68 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrEventTypeMismatch = errors.New("event type mismatch")
+)
+
+// HandlerFunc ทำให้ฟังก์ชันธรรมดาเป็น IntegrationEventHandler ได้
+type HandlerFunc func(ctx context.Context, event Event) error
+
+func (f HandlerFunc) Handle(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// TypedHandler คือ handler ที่รับ event เป็น type จริง ไม่ต้อง type assert เอง
+type TypedHandler[T Event] interface {
+	Handle(ctx context.Context, event T) error
+}
+
+// TypedHandlerFunc ทำให้ฟังก์ชันธรรมดาเป็น TypedHandler ได้
+type TypedHandlerFunc[T Event] func(ctx context.Context, event T) error
+
+func (f TypedHandlerFunc[T]) Handle(ctx context.Context, event T) error {
+	return f(ctx, event)
+}
+
+// Typed แปลง TypedHandler เป็น IntegrationEventHandler
+// ถ้าได้รับ event ที่ไม่ใช่ T จะคืน ErrEventTypeMismatch
+func Typed[T Event](handler TypedHandler[T]) IntegrationEventHandler {
+	return HandlerFunc(func(ctx context.Context, event Event) error {
+		e, ok := event.(T)
+		if !ok {
+			var want T
+			return fmt.Errorf("%w: %s expected %T, got %T", ErrEventTypeMismatch, event.EventName(), want, event)
+		}
+		return handler.Handle(ctx, e)
+	})
+}
+
+// CheckType ตรวจว่า type ที่ลงทะเบียนไว้ใน registry สำหรับ eventName คือ T
+func CheckType[T Event](registry *Registry, eventName EventName) error {
+	event, _, err := registry.New(eventName)
+	if err != nil {
+		return err
+	}
+	if _, ok := event.(T); !ok {
+		var want T
+		return fmt.Errorf("%w: %s is registered as %T, subscriber expects %T", ErrEventTypeMismatch, eventName, event, want)
+	}
+	return nil
+}
+
+// SubscribeTyped ลงทะเบียน TypedHandler กับ event bus (ใช้กับชื่อ event ตรงตัวเท่านั้น ไม่รองรับ pattern)
+// และคืน error ทันทีถ้า T ไม่ตรงกับ type ที่ publisher ลงทะเบียนไว้ใน DefaultRegistry
+func SubscribeTyped[T Event](bus EventBus, eventName EventName, handler TypedHandler[T], opts ...SubscribeOption) (Subscription, error) {
+	if err := CheckType[T](DefaultRegistry, eventName); err != nil {
+		return nil, err
+	}
+
+	// ตั้งชื่อ subscription ตาม handler ตัวจริง ไม่ใช่ตัวแปลง
+	opts = append([]SubscribeOption{WithHandlerName(fmt.Sprintf("%T", handler))}, opts...)
+	return bus.Subscribe(eventName, Typed(handler), opts...), nil
+}
@@ -0,0 +1,36 @@
+package eventlog
+
+import (
+	"context"
+	"go-mma/shared/common/eventbus"
+)
+
+// loggingBus บันทึก event ลง log ก่อนส่งต่อให้ event bus ตัวจริง
+type loggingBus struct {
+	eventbus.EventBus
+	store Store
+}
+
+// NewBus ครอบ event bus ให้ทุก event ที่ Publish ถูกบันทึกลง store ด้วย
+// ถ้าบันทึกไม่สำเร็จจะไม่ publish และคืน error (outbox relay จะลองใหม่ในรอบถัดไป)
+func NewBus(bus eventbus.EventBus, store Store) eventbus.EventBus {
+	return &loggingBus{
+		EventBus: bus,
+		store:    store,
+	}
+}
+
+func (b *loggingBus) Publish(ctx context.Context, event eventbus.Event) error {
+	if err := b.store.Append(ctx, event); err != nil {
+		return err
+	}
+	return b.EventBus.Publish(ctx, event)
+}
+
+// Start ส่งต่อให้ event bus ที่ต้องเปิด connection ก่อนใช้งาน เช่น PostgresEventBus
+func (b *loggingBus) Start() error {
+	if s, ok := b.EventBus.(interface{ Start() error }); ok {
+		return s.Start()
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package eventlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/logger"
+
+	"go.uber.org/zap"
+)
+
+const replayBatchSize = 100
+
+var (
+	ErrHandlerNameRequired = errs.InputValidationError("handler name is required")
+	ErrReplayFilterEmpty   = errs.InputValidationError("at least one of event name, event id or time range is required")
+)
+
+// Service ใช้ดูประวัติ event และ replay event เก่าให้ subscriber ที่ระบุ
+type Service interface {
+	History(ctx context.Context, filter Filter) ([]*Entry, error)
+	Replay(ctx context.Context, handlerName string, filter Filter) (int, error)
+}
+
+type service struct {
+	store    Store
+	eventBus eventbus.EventBus
+	codec    eventbus.Codec
+}
+
+func NewService(store Store, eventBus eventbus.EventBus, codec eventbus.Codec) Service {
+	return &service{
+		store:    store,
+		eventBus: eventBus,
+		codec:    codec,
+	}
+}
+
+func (s *service) History(ctx context.Context, filter Filter) ([]*Entry, error) {
+	return s.store.List(ctx, filter)
+}
+
+// Replay ส่ง event ที่ตรงกับ filter ให้ handlerName ตามลำดับที่ถูกบันทึก แบบ synchronous
+// จะหยุดทันทีที่ handler คืน error และคืนจำนวน event ที่ replay สำเร็จก่อนหน้านั้น
+// filter.Limit และ filter.Offset จะถูกละเลย
+func (s *service) Replay(ctx context.Context, handlerName string, filter Filter) (int, error) {
+	if handlerName == "" {
+		return 0, ErrHandlerNameRequired
+	}
+	if filter.EventName == "" && filter.EventID == "" && filter.From == nil && filter.To == nil {
+		return 0, ErrReplayFilterEmpty
+	}
+
+	filter.Limit = replayBatchSize
+	filter.Offset = 0
+
+	replayed := 0
+	for {
+		entries, err := s.store.List(ctx, filter)
+		if err != nil {
+			return replayed, err
+		}
+
+		for _, e := range entries {
+			if err := s.replay(ctx, handlerName, e); err != nil {
+				return replayed, err
+			}
+			replayed++
+			filter.AfterID = e.ID
+		}
+
+		if len(entries) < replayBatchSize {
+			break
+		}
+	}
+
+	logger.Log.Info("events replayed",
+		zap.String("handler", handlerName),
+		zap.Int("count", replayed),
+	)
+	return replayed, nil
+}
+
+func (s *service) replay(ctx context.Context, handlerName string, e *Entry) error {
+	event, env, err := s.codec.Decode(e.Payload)
+	if err != nil {
+		return errs.OperationFailedError(fmt.Sprintf("failed to decode event %s", e.EventID), err)
+	}
+
+	ctx = eventbus.ContextWithReplay(eventbus.ContextWithMetadata(ctx, env.Metadata))
+	if err := s.eventBus.Deliver(ctx, handlerName, event); err != nil {
+		if errors.Is(err, eventbus.ErrSubscriberNotFound) {
+			return errs.ResourceNotFoundError(err.Error(), err)
+		}
+		return errs.OperationFailedError(fmt.Sprintf("failed to replay event %s: %v", e.EventID, err), err)
+	}
+	return nil
+}
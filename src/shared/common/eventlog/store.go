@@ -0,0 +1,119 @@
+package eventlog
+
+import (
+	"context"
+	"fmt"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/storage/sqldb/transactor"
+	"strings"
+	"time"
+)
+
+// Entry คือ integration event หนึ่งรายการที่ถูก publish แล้ว
+type Entry struct {
+	ID         int64              `db:"id"`
+	EventID    string             `db:"event_id"`
+	EventName  eventbus.EventName `db:"event_name"`
+	Payload    []byte             `db:"payload"` // envelope ที่ encode ด้วย Codec
+	OccurredAt time.Time          `db:"occurred_at"`
+	RecordedAt time.Time          `db:"recorded_at"`
+}
+
+// Filter ใช้เลือก event จาก log ทุกเงื่อนไขเป็น optional และถูก AND กัน
+type Filter struct {
+	EventName eventbus.EventName
+	EventID   string
+	From      *time.Time // occurred_at >= From
+	To        *time.Time // occurred_at < To
+	AfterID   int64      // ใช้แบ่งหน้าแบบ cursor ตาม id
+	Limit     int
+	Offset    int
+}
+
+// Store คือ log แบบ append-only ของทุก integration event ที่ถูก publish
+type Store interface {
+	Append(ctx context.Context, event eventbus.Event) error
+	List(ctx context.Context, filter Filter) ([]*Entry, error)
+}
+
+type sqlStore struct {
+	dbCtx transactor.DBContext
+	codec eventbus.Codec
+}
+
+func NewStore(dbCtx transactor.DBContext, codec eventbus.Codec) Store {
+	return &sqlStore{
+		dbCtx: dbCtx,
+		codec: codec,
+	}
+}
+
+// Append ข้าม event ที่มี event_id ซ้ำ เพราะ outbox relay อาจ publish event เดิมซ้ำได้
+func (s *sqlStore) Append(ctx context.Context, event eventbus.Event) error {
+	payload, err := s.codec.Encode(event, eventbus.MetadataFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("event log: %w", err)
+	}
+
+	query := `
+	INSERT INTO public.event_log (event_id, event_name, payload, occurred_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (event_id) DO NOTHING
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err = s.dbCtx(ctx).ExecContext(ctx, query, event.EventID(), event.EventName(), payload, event.OccurredAt())
+	if err != nil {
+		return errs.HandleDBError(fmt.Errorf("failed to append event log: %w", err))
+	}
+	return nil
+}
+
+func (s *sqlStore) List(ctx context.Context, filter Filter) ([]*Entry, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.EventName != "" {
+		where("event_name = $%d", filter.EventName)
+	}
+	if filter.EventID != "" {
+		where("event_id = $%d", filter.EventID)
+	}
+	if filter.From != nil {
+		where("occurred_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		where("occurred_at < $%d", *filter.To)
+	}
+	if filter.AfterID > 0 {
+		where("id > $%d", filter.AfterID)
+	}
+
+	query := `
+	SELECT *
+	FROM public.event_log
+	`
+	if len(conds) > 0 {
+		query += "WHERE " + strings.Join(conds, " AND ") + "\n"
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf("ORDER BY id\n\tLIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	entries := make([]*Entry, 0)
+	if err := s.dbCtx(ctx).SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, errs.HandleDBError(fmt.Errorf("failed to list event log: %w", err))
+	}
+	return entries, nil
+}
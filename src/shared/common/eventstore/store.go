@@ -0,0 +1,163 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-mma/shared/common/domain"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/storage/sqldb/transactor"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type eventRow struct {
+	StreamID   string    `db:"stream_id"`
+	Version    int       `db:"version"`
+	EventName  string    `db:"event_name"`
+	Payload    []byte    `db:"payload"`
+	OccurredAt time.Time `db:"occurred_at"`
+}
+
+type snapshotRow struct {
+	StreamID string `db:"stream_id"`
+	Version  int    `db:"version"`
+	State    []byte `db:"state"`
+}
+
+type sqlStore struct {
+	dbCtx transactor.DBContext
+}
+
+// NewStore สร้าง EventStore ที่เก็บ event ในตาราง event_store และ snapshot ในตาราง event_store_snapshots
+// ถ้าเรียกภายใน transaction event จะถูก commit หรือ rollback พร้อมกับข้อมูลอื่นของ transaction นั้น
+func NewStore(dbCtx transactor.DBContext) domain.EventStore {
+	return &sqlStore{
+		dbCtx: dbCtx,
+	}
+}
+
+func (s *sqlStore) Append(ctx context.Context, streamID string, expectedVersion int, events []domain.RecordedEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var current int
+	query := `SELECT COALESCE(MAX(version), 0) FROM public.event_store WHERE stream_id = $1`
+	if err := s.dbCtx(ctx).GetContext(ctx, &current, query, streamID); err != nil {
+		return errs.HandleDBError(fmt.Errorf("failed to get stream version: %w", err))
+	}
+	if current != expectedVersion {
+		return conflictError(streamID, expectedVersion, current)
+	}
+
+	// insert ทุก event ใน statement เดียว unique (stream_id, version) กันการเขียนพร้อมกันที่ผ่านการตรวจข้างบนมาได้
+	values := make([]string, 0, len(events))
+	args := make([]any, 0, len(events)*5)
+	for i, e := range events {
+		n := i * 5
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5))
+		args = append(args, streamID, e.Version, e.EventName, e.Payload, e.OccurredAt)
+	}
+	query = `
+	INSERT INTO public.event_store (stream_id, version, event_name, payload, occurred_at)
+	VALUES ` + strings.Join(values, ", ")
+
+	if _, err := s.dbCtx(ctx).ExecContext(ctx, query, args...); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return conflictError(streamID, expectedVersion, -1)
+		}
+		return errs.HandleDBError(fmt.Errorf("failed to append events: %w", err))
+	}
+	return nil
+}
+
+func (s *sqlStore) Load(ctx context.Context, streamID string, afterVersion int) ([]domain.RecordedEvent, error) {
+	query := `
+	SELECT stream_id, version, event_name, payload, occurred_at
+	FROM public.event_store
+	WHERE stream_id = $1
+	AND version > $2
+	ORDER BY version
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var rows []eventRow
+	if err := s.dbCtx(ctx).SelectContext(ctx, &rows, query, streamID, afterVersion); err != nil {
+		return nil, errs.HandleDBError(fmt.Errorf("failed to load events: %w", err))
+	}
+
+	events := make([]domain.RecordedEvent, 0, len(rows))
+	for _, r := range rows {
+		events = append(events, domain.RecordedEvent{
+			StreamID:   r.StreamID,
+			Version:    r.Version,
+			EventName:  domain.EventName(r.EventName),
+			Payload:    r.Payload,
+			OccurredAt: r.OccurredAt,
+		})
+	}
+	return events, nil
+}
+
+func (s *sqlStore) LoadSnapshot(ctx context.Context, streamID string) (*domain.Snapshot, error) {
+	query := `
+	SELECT stream_id, version, state
+	FROM public.event_store_snapshots
+	WHERE stream_id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var row snapshotRow
+	if err := s.dbCtx(ctx).QueryRowxContext(ctx, query, streamID).StructScan(&row); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errs.HandleDBError(fmt.Errorf("failed to load snapshot: %w", err))
+	}
+	return &domain.Snapshot{
+		StreamID: row.StreamID,
+		Version:  row.Version,
+		State:    row.State,
+	}, nil
+}
+
+// SaveSnapshot เก็บเฉพาะ snapshot ล่าสุดของแต่ละ stream
+func (s *sqlStore) SaveSnapshot(ctx context.Context, snapshot *domain.Snapshot) error {
+	query := `
+	INSERT INTO public.event_store_snapshots (stream_id, version, state)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (stream_id) DO UPDATE
+	SET version = EXCLUDED.version,
+		state = EXCLUDED.state,
+		created_at = current_timestamp
+	WHERE event_store_snapshots.version < EXCLUDED.version
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if _, err := s.dbCtx(ctx).ExecContext(ctx, query, snapshot.StreamID, snapshot.Version, snapshot.State); err != nil {
+		return errs.HandleDBError(fmt.Errorf("failed to save snapshot: %w", err))
+	}
+	return nil
+}
+
+// conflictError คืน errs.ErrConcurrency ให้ mediator.RetryOnConflict ลองใหม่ได้ (actual < 0 คือไม่ทราบ)
+func conflictError(streamID string, expected int, actual int) error {
+	if actual < 0 {
+		return errs.ConcurrencyConflictError(fmt.Sprintf("stream %s was modified by another request (expected version %d)", streamID, expected))
+	}
+	return errs.ConcurrencyConflictError(fmt.Sprintf("stream %s is at version %d, expected %d", streamID, actual, expected))
+}
@@ -3,6 +3,7 @@ module go-mma/shared/common
 go 1.24.1
 
 require (
+	github.com/fxamacker/cbor/v2 v2.7.0
 	github.com/gofiber/fiber/v3 v3.0.0-beta.4
 	github.com/jmoiron/sqlx v1.4.0
 	github.com/lib/pq v1.10.9
@@ -12,7 +13,6 @@ require (
 
 require (
 	github.com/andybalholm/brotli v1.1.1 // indirect
-	github.com/fxamacker/cbor/v2 v2.7.0 // indirect
 	github.com/gofiber/schema v1.2.0 // indirect
 	github.com/gofiber/utils/v2 v2.0.0-beta.7 // indirect
 	github.com/google/uuid v1.6.0 // indirect
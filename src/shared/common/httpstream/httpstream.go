@@ -0,0 +1,120 @@
+package httpstream
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/logger"
+	"iter"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+// flushEvery คือจำนวนรายการที่เขียนก่อนส่งออกไปให้ client หนึ่งครั้ง
+const flushEvery = 100
+
+// NDJSON เขียน seq เป็น application/x-ndjson หนึ่งรายการต่อบรรทัด
+// error ของรายการแรกจะคืนออกไปให้ middleware ตอบเป็น HTTP error ตามปกติ
+// error หลังจากเริ่มส่งข้อมูลแล้วจะเขียนเป็นบรรทัดสุดท้ายในรูป {"error": {...}}
+func NDJSON[T any](c fiber.Ctx, seq iter.Seq2[T, error]) error {
+	return write(c, "application/x-ndjson", seq,
+		func(w *bufio.Writer) itemWriter[T] {
+			enc := json.NewEncoder(w)
+			return itemWriter[T]{
+				begin: func() error { return nil },
+				item:  func(item T) error { return enc.Encode(item) },
+				flush: func() error { return nil },
+				fail: func(err error) {
+					_ = enc.Encode(map[string]*errs.AppError{"error": toAppError(err)})
+				},
+			}
+		},
+	)
+}
+
+// CSV เขียน seq เป็น text/csv ให้ดาวน์โหลดเป็นไฟล์ filename
+// header คือชื่อคอลัมน์ และ toRow แปลงแต่ละรายการเป็นค่าของคอลัมน์ตามลำดับเดียวกัน
+// CSV ไม่มีที่ให้บอก error หลังจากเริ่มส่งข้อมูลแล้ว จึงทำได้เพียงบันทึก log และหยุดเขียน
+func CSV[T any](c fiber.Ctx, filename string, header []string, toRow func(T) []string, seq iter.Seq2[T, error]) error {
+	c.Attachment(filename)
+	return write(c, "text/csv; charset=utf-8", seq,
+		func(w *bufio.Writer) itemWriter[T] {
+			cw := csv.NewWriter(w)
+			return itemWriter[T]{
+				begin: func() error { return cw.Write(header) },
+				item:  func(item T) error { return cw.Write(toRow(item)) },
+				flush: func() error {
+					cw.Flush()
+					return cw.Error()
+				},
+				fail: func(error) {},
+			}
+		},
+	)
+}
+
+type itemWriter[T any] struct {
+	begin func() error
+	item  func(T) error
+	flush func() error // ส่งข้อมูลที่ค้างใน writer ของ format ลง bufio.Writer
+	fail  func(error)
+}
+
+func write[T any](c fiber.Ctx, contentType string, seq iter.Seq2[T, error], newWriter func(*bufio.Writer) itemWriter[T]) error {
+	// อ่านรายการแรกก่อนเขียน header เพื่อให้ error ตอนเปิด stream (เช่น ไม่มีสิทธิ์) ตอบเป็น status code ที่ถูกต้อง
+	next, stop := iter.Pull2(seq)
+	item, err, ok := next()
+	if err != nil {
+		stop()
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		defer stop()
+
+		iw := newWriter(w)
+		if err := iw.begin(); err != nil {
+			logger.Log.Error("failed to write stream header", zap.Error(err))
+			return
+		}
+		flush := func() error {
+			if err := iw.flush(); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+
+		for n := 1; ok; n++ {
+			if err != nil {
+				logger.Log.Error("stream aborted", zap.Int("written", n-1), zap.Error(err))
+				iw.fail(err)
+				_ = flush()
+				return
+			}
+			if err := iw.item(item); err != nil {
+				logger.Log.Error("failed to write stream item", zap.Error(err))
+				return
+			}
+			if n%flushEvery == 0 {
+				// client ปิด connection ไปแล้ว หยุดอ่านเพื่อคืน resource ของ handler
+				if err := flush(); err != nil {
+					logger.Log.Warn(fmt.Sprintf("stream client disconnected: %v", err))
+					return
+				}
+			}
+			item, err, ok = next()
+		}
+		_ = flush()
+	})
+}
+
+func toAppError(err error) *errs.AppError {
+	if appErr, ok := err.(*errs.AppError); ok {
+		return appErr
+	}
+	return errs.OperationFailedError(err.Error())
+}
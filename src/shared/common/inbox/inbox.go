@@ -0,0 +1,97 @@
+package inbox
+
+import (
+	"context"
+	"fmt"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/logger"
+	"go-mma/shared/common/storage/sqldb/transactor"
+
+	"go.uber.org/zap"
+)
+
+// Inbox ทำให้ IntegrationEventHandler เป็น idempotent
+// โดยจำ EventID ที่ handler แต่ละตัวประมวลผลไปแล้วในตาราง inbox
+type Inbox interface {
+	Wrap(handlerName string, handler eventbus.IntegrationEventHandler) eventbus.IntegrationEventHandler
+}
+
+type sqlInbox struct {
+	transactor transactor.Transactor
+	dbCtx      transactor.DBContext
+}
+
+func NewInbox(t transactor.Transactor, dbCtx transactor.DBContext) Inbox {
+	return &sqlInbox{
+		transactor: t,
+		dbCtx:      dbCtx,
+	}
+}
+
+// Wrap คืน handler ตัวใหม่ที่ข้าม event ที่ handlerName เคยประมวลผลแล้ว
+// handlerName ต้องไม่ซ้ำกัน และต้องคงที่ข้ามการ deploy
+func (i *sqlInbox) Wrap(handlerName string, handler eventbus.IntegrationEventHandler) eventbus.IntegrationEventHandler {
+	return &idempotentHandler{
+		inbox:       i,
+		handlerName: handlerName,
+		next:        handler,
+	}
+}
+
+// WrapTyped เหมือน Wrap แต่ใช้กับ eventbus.TypedHandler
+func WrapTyped[T eventbus.Event](ib Inbox, handlerName string, handler eventbus.TypedHandler[T]) eventbus.TypedHandler[T] {
+	wrapped := ib.Wrap(handlerName, eventbus.Typed(handler))
+	return eventbus.TypedHandlerFunc[T](func(ctx context.Context, event T) error {
+		return wrapped.Handle(ctx, event)
+	})
+}
+
+type idempotentHandler struct {
+	inbox       *sqlInbox
+	handlerName string
+	next        eventbus.IntegrationEventHandler
+}
+
+func (h *idempotentHandler) Handle(ctx context.Context, event eventbus.Event) error {
+	return h.inbox.transactor.WithinTransaction(ctx, func(ctx context.Context, _ func(transactor.PostCommitHook)) error {
+		recorded, err := h.inbox.record(ctx, h.handlerName, event)
+		if err != nil {
+			return err
+		}
+
+		// event ที่ถูก replay ตั้งใจให้ประมวลผลซ้ำ
+		if !recorded && !eventbus.IsReplay(ctx) {
+			logger.Log.Info("skip duplicate event",
+				zap.String("handler", h.handlerName),
+				zap.String("eventId", event.EventID()),
+				zap.String("eventName", string(event.EventName())),
+			)
+			return nil
+		}
+
+		// ถ้า handler คืน error จะ rollback ทั้งข้อมูลของ handler และ record ใน inbox
+		// ทำให้ event นี้ถูกประมวลผลใหม่ได้เมื่อถูกส่งมาอีกครั้ง
+		return h.next.Handle(ctx, event)
+	})
+}
+
+// record คืนค่า false ถ้า event นี้เคยถูกบันทึกไว้แล้ว
+func (i *sqlInbox) record(ctx context.Context, handlerName string, event eventbus.Event) (bool, error) {
+	query := `
+	INSERT INTO public.inbox (handler_name, event_id, event_name)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (handler_name, event_id) DO NOTHING
+	`
+
+	result, err := i.dbCtx(ctx).ExecContext(ctx, query, handlerName, event.EventID(), event.EventName())
+	if err != nil {
+		return false, errs.HandleDBError(fmt.Errorf("failed to insert inbox event: %w", err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errs.HandleDBError(fmt.Errorf("failed to insert inbox event: %w", err))
+	}
+	return affected > 0, nil
+}
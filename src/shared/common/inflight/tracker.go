@@ -0,0 +1,91 @@
+package inflight
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrClosed = errors.New("tracker is closed")
+)
+
+// Tracker นับงานที่รันอยู่ใน background เพื่อให้รอจนเสร็จได้ตอน shutdown
+type Tracker struct {
+	mu     sync.Mutex
+	active int
+	closed bool
+	idle   chan struct{}
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// Go รัน fn ใน goroutine ใหม่ และคืน ErrClosed ถ้า Drain ถูกเรียกไปแล้ว
+func (t *Tracker) Go(fn func()) error {
+	if err := t.Add(); err != nil {
+		return err
+	}
+
+	go func() {
+		defer t.Done()
+		fn()
+	}()
+	return nil
+}
+
+// Add นับงานเพิ่ม 1 งาน ใช้กับงานที่ไม่ได้รันผ่าน Go เช่นงานที่เข้าคิว
+// ต้องเรียก Done เมื่องานเสร็จเสมอ
+func (t *Tracker) Add() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return ErrClosed
+	}
+	t.active++
+	return nil
+}
+
+// Closed บอกว่า Drain ถูกเรียกไปแล้วหรือยัง
+func (t *Tracker) Closed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closed
+}
+
+// Drain ปฏิเสธงานใหม่ และรอให้งานที่ค้างอยู่ทำงานเสร็จหรือจนกว่า ctx จะหมดเวลา
+// คืนจำนวนงานที่ยังไม่เสร็จ (ถูกทิ้ง) เมื่อ ctx หมดเวลา
+func (t *Tracker) Drain(ctx context.Context) (int, error) {
+	t.mu.Lock()
+	if !t.closed {
+		t.closed = true
+		t.idle = make(chan struct{})
+		if t.active == 0 {
+			close(t.idle)
+		}
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return 0, nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return t.active, ctx.Err()
+	}
+}
+
+// Done บอกว่างานที่นับด้วย Add เสร็จแล้ว
+func (t *Tracker) Done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.active--
+	if t.closed && t.active == 0 {
+		close(t.idle)
+	}
+}
@@ -0,0 +1,88 @@
+package mediator
+
+import (
+	"context"
+	"go-mma/shared/common/auth"
+	"go-mma/shared/common/errs"
+)
+
+// PermissionRequirer คือ request ที่ต้องการสิทธิ์ตามที่ระบุ (ต้องมีครบทุกตัว)
+type PermissionRequirer interface {
+	RequiredPermissions() []auth.Permission
+}
+
+// PolicyRequirer คือ request ที่ตรวจสิทธิ์ด้วยเงื่อนไขของตัวเอง เช่น เป็นเจ้าของข้อมูลหรือไม่
+// คืน error ที่ไม่ใช่ nil เมื่อไม่อนุญาต
+type PolicyRequirer interface {
+	Authorize(ctx context.Context, principal *auth.Principal) error
+}
+
+// Policy คือเงื่อนไขการตรวจสิทธิ์ที่กำหนดตอนลงทะเบียน handler
+type Policy func(ctx context.Context, principal *auth.Principal, request any) error
+
+// Authorization ตรวจสิทธิ์ของ principal ใน context กับ request ที่ประกาศ PermissionRequirer หรือ PolicyRequirer
+// request ที่ไม่ได้ประกาศอะไรไว้จะผ่านเสมอ
+func Authorization() Behavior {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request any) (any, error) {
+			pr, needPerms := request.(PermissionRequirer)
+			po, needPolicy := request.(PolicyRequirer)
+			if !needPerms && !needPolicy {
+				return next(ctx, request)
+			}
+
+			principal, err := requirePrincipal(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			if needPerms {
+				for _, perm := range pr.RequiredPermissions() {
+					if !principal.HasPermission(perm) {
+						return nil, errs.NewAuthorizationError("permission denied: " + string(perm))
+					}
+				}
+			}
+
+			if needPolicy {
+				if err := po.Authorize(ctx, principal); err != nil {
+					return nil, asAuthorizationError(err)
+				}
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// RequirePolicy ตรวจสิทธิ์ด้วย policy ก่อนเรียก handler ใช้คู่กับ WithBehaviors ตอนลงทะเบียน
+func RequirePolicy(policy Policy) Behavior {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request any) (any, error) {
+			principal, err := requirePrincipal(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if err := policy(ctx, principal, request); err != nil {
+				return nil, asAuthorizationError(err)
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+func requirePrincipal(ctx context.Context) (*auth.Principal, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, errs.AuthenticationError("authentication required")
+	}
+	return principal, nil
+}
+
+// asAuthorizationError ทำให้ error จาก policy ถูก map เป็น 403 เสมอ ยกเว้นเป็น AppError อยู่แล้ว
+func asAuthorizationError(err error) error {
+	if _, ok := err.(*errs.AppError); ok {
+		return err
+	}
+	return errs.NewAuthorizationError(err.Error(), err)
+}
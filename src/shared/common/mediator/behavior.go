@@ -0,0 +1,156 @@
+package mediator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/logger"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrHandlerPanic = errors.New("request handler panic")
+)
+
+// HandlerFunc คือขั้นหนึ่งใน pipeline ของ Send ที่รับ request และคืน response แบบไม่ระบุ type
+type HandlerFunc func(ctx context.Context, request any) (any, error)
+
+// Behavior ห่อ pipeline ของ Send เพื่อเพิ่มพฤติกรรมร่วม เช่น logging หรือ validation
+type Behavior func(next HandlerFunc) HandlerFunc
+
+// TypedHandlerFunc คือ HandlerFunc ที่รู้ type ของ request และ response
+type TypedHandlerFunc[TRequest any, TResponse any] func(ctx context.Context, request TRequest) (TResponse, error)
+
+// TypedBehavior คือ Behavior ที่ใช้กับ request type เดียว ไม่ต้อง type assert เอง
+type TypedBehavior[TRequest any, TResponse any] func(next TypedHandlerFunc[TRequest, TResponse]) TypedHandlerFunc[TRequest, TResponse]
+
+// UseBehaviorFor เพิ่ม behavior เฉพาะ TRequest ให้ m (ทำงานหลัง behavior ที่เพิ่มด้วย Use)
+func UseBehaviorFor[TRequest any, TResponse any](m *Mediator, b TypedBehavior[TRequest, TResponse]) error {
+	var req TRequest
+	reqType := reflect.TypeOf(req)
+
+	return m.useFor(reqType, func(next HandlerFunc) HandlerFunc {
+		typedNext := func(ctx context.Context, request TRequest) (TResponse, error) {
+			return typedResult[TResponse](next(ctx, request))
+		}
+		handle := b(typedNext)
+		return func(ctx context.Context, request any) (any, error) {
+			typedReq, ok := request.(TRequest)
+			if !ok {
+				return nil, errors.New("invalid request type")
+			}
+			return handle(ctx, typedReq)
+		}
+	})
+}
+
+// chain ห่อ handler ด้วย behavior ตามลำดับ ตัวแรกจะอยู่นอกสุด
+func chain(handler HandlerFunc, global []Behavior, typed []Behavior) HandlerFunc {
+	bs := append(append([]Behavior(nil), global...), typed...)
+	for i := len(bs) - 1; i >= 0; i-- {
+		handler = bs[i](handler)
+	}
+	return handler
+}
+
+func typedResult[TResponse any](result any, err error) (TResponse, error) {
+	var empty TResponse
+	if err != nil {
+		return empty, err
+	}
+	if result == nil {
+		return empty, nil
+	}
+	typedRes, ok := result.(TResponse)
+	if !ok {
+		return empty, errors.New("invalid response type")
+	}
+	return typedRes, nil
+}
+
+func requestName(request any) string {
+	return fmt.Sprintf("%T", request)
+}
+
+// Recovery แปลง panic ใน handler เป็น error แทนที่จะทำให้ทั้ง process ล่ม
+func Recovery() Behavior {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request any) (res any, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Log.Error("request handler panic",
+						zap.String("request", requestName(request)),
+						zap.Any("panic", r),
+						zap.ByteString("stack", debug.Stack()),
+					)
+					res, err = nil, fmt.Errorf("%w: %v", ErrHandlerPanic, r)
+				}
+			}()
+			return next(ctx, request)
+		}
+	}
+}
+
+// Logging บันทึก log ทุก request ที่ส่งผ่าน mediator พร้อมเวลาที่ใช้ และ error ถ้ามี
+// handler จึงไม่ต้อง log error เองก่อน return
+func Logging() Behavior {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request any) (any, error) {
+			start := time.Now()
+			res, err := next(ctx, request)
+
+			fields := []zap.Field{
+				zap.String("request", requestName(request)),
+				zap.Duration("latency", time.Since(start)),
+			}
+			if err != nil {
+				logger.Log.Error("request handled with error", append(fields, zap.Error(err))...)
+				return res, err
+			}
+			logger.Log.Debug("request handled", fields...)
+			return res, nil
+		}
+	}
+}
+
+// Validator คือ request ที่ตรวจสอบความถูกต้องของตัวเองได้
+type Validator interface {
+	Validate() error
+}
+
+// Validation เรียก Validate() ของ request (ถ้ามี) ก่อนส่งให้ handler
+func Validation() Behavior {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request any) (any, error) {
+			if v, ok := request.(Validator); ok {
+				if err := v.Validate(); err != nil {
+					return nil, errs.InputValidationError(strings.Join(strings.Split(err.Error(), "\n"), ", "))
+				}
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// MetricsRecorder รับค่าเวลาที่ handler ใช้ เพื่อส่งต่อให้ระบบ metrics ที่ใช้อยู่
+type MetricsRecorder interface {
+	ObserveRequest(requestName string, duration time.Duration, err error)
+}
+
+// Metrics วัดเวลาที่ใช้ในแต่ละ request แล้วส่งให้ recorder
+func Metrics(recorder MetricsRecorder) Behavior {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request any) (any, error) {
+			start := time.Now()
+			res, err := next(ctx, request)
+			recorder.ObserveRequest(requestName(request), time.Since(start), err)
+			return res, err
+		}
+	}
+}
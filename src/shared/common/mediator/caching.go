@@ -0,0 +1,55 @@
+package mediator
+
+import (
+	"context"
+	"go-mma/shared/common/cache"
+	"reflect"
+	"time"
+)
+
+// Cacheable คือ query ที่ผลลัพธ์เก็บไว้ใน cache ได้
+// CacheKey ต้องไม่ซ้ำกับ query อื่น และควรตรงกับ key ที่ใช้ invalidate จาก domain event
+type Cacheable interface {
+	CacheKey() string
+	CacheTTL() time.Duration
+}
+
+// Caching คืนผลลัพธ์จาก cache ถ้ามี ไม่อย่างนั้นจะเรียก handler แล้วเก็บผลลัพธ์ที่ไม่ error ไว้
+// ใช้กับ query เท่านั้น ข้อมูลอาจเก่าได้ไม่เกิน CacheTTL ถ้าไม่ถูก invalidate
+// ผลลัพธ์ถูกใช้ร่วมกันทุกผู้เรียก ผู้เรียกจึงห้ามแก้ไขค่าที่ได้กลับไป
+// request ที่ลงทะเบียนด้วย RegisterRemote จะไม่ถูก cache
+func Caching(c cache.Cache) Behavior {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request any) (any, error) {
+			q, ok := request.(Cacheable)
+			if !ok || cachingSkipped(ctx, request) {
+				return next(ctx, request)
+			}
+
+			key := q.CacheKey()
+			if res, ok := c.Get(key); ok {
+				return res, nil
+			}
+
+			res, err := next(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			c.Set(key, res, q.CacheTTL())
+			return res, nil
+		}
+	}
+}
+
+type noCachingKey struct{}
+
+// withoutCaching บอก Caching ว่าไม่ต้อง cache request ของ reqType
+// ผูกกับ type เพื่อไม่ให้มีผลกับ request อื่นที่ handler ส่งต่อด้วย ctx เดียวกัน
+func withoutCaching(ctx context.Context, reqType reflect.Type) context.Context {
+	return context.WithValue(ctx, noCachingKey{}, reqType)
+}
+
+func cachingSkipped(ctx context.Context, request any) bool {
+	reqType, ok := ctx.Value(noCachingKey{}).(reflect.Type)
+	return ok && reqType == reflect.TypeOf(request)
+}
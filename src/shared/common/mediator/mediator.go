@@ -2,9 +2,18 @@ package mediator
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"go-mma/shared/common/errs"
 	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrHandlerRegistered = errors.New("handler is already registered for request")
+	ErrFrozen            = errors.New("mediator is frozen")
 )
 
 // You can define an empty struct to represent no response.
@@ -14,44 +23,202 @@ type RequestHandler[TRequest any, TResponse any] interface {
 	Handle(ctx context.Context, request TRequest) (TResponse, error)
 }
 
-var handlers = map[reflect.Type]func(ctx context.Context, req interface{}) (interface{}, error){}
+// Mediator เก็บ handler และ behavior ของแต่ละ request type ใช้งานพร้อมกันหลาย goroutine ได้
+// หลังจาก Freeze แล้วจะเพิ่ม handler หรือ behavior ไม่ได้อีก
+type Mediator struct {
+	mu             sync.RWMutex
+	handlers       map[reflect.Type]HandlerFunc // ห่อด้วย behavior ที่ระบุตอนลงทะเบียนแล้ว
+	behaviors      []Behavior
+	typedBehaviors map[reflect.Type][]Behavior
+	frozen         bool
+
+	notificationHandlers   map[reflect.Type][]notificationHandler
+	publishStrategy        PublishStrategy
+	notificationStrategies map[reflect.Type]PublishStrategy // strategy เฉพาะ notification type ที่ใช้แทน publishStrategy
+
+	exposed  map[string]remoteHandler // request ที่ Server เปิดให้ process อื่นเรียก
+	uncached map[reflect.Type]bool    // request ที่ไม่ผ่าน Caching เช่นที่ส่งไป process อื่น
+}
+
+func New() *Mediator {
+	return &Mediator{
+		handlers:       make(map[reflect.Type]HandlerFunc),
+		typedBehaviors: make(map[reflect.Type][]Behavior),
+
+		notificationHandlers:   make(map[reflect.Type][]notificationHandler),
+		publishStrategy:        Sequential(),
+		notificationStrategies: make(map[reflect.Type]PublishStrategy),
+
+		exposed:  make(map[string]remoteHandler),
+		uncached: make(map[reflect.Type]bool),
+	}
+}
+
+var defaultMediator atomic.Pointer[Mediator]
+
+func init() {
+	defaultMediator.Store(New())
+}
+
+// Default คืน Mediator ที่ Register, Send, RegisterNotification, Publish และ Stream ระดับ package ใช้
+func Default() *Mediator {
+	return defaultMediator.Load()
+}
+
+// SetDefault เปลี่ยน Mediator ที่ฟังก์ชันระดับ package ใช้ (Application เรียกตอนเริ่มระบบ)
+func SetDefault(m *Mediator) {
+	defaultMediator.Store(m)
+}
+
+// Use เพิ่ม behavior ที่ครอบทุก request ตัวแรกจะอยู่นอกสุด
+func (m *Mediator) Use(bs ...Behavior) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.frozen {
+		return ErrFrozen
+	}
+	m.behaviors = append(m.behaviors, bs...)
+	return nil
+}
+
+// Freeze ปิดการลงทะเบียน handler และ behavior เพิ่ม
+func (m *Mediator) Freeze() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.frozen = true
+}
+
+func (m *Mediator) Frozen() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.frozen
+}
+
+// register เก็บ handler ของ reqType ถ้า expose ไม่เป็น nil จะเปิดให้ Server เรียกได้ด้วย
+// uncached ทำให้ request นี้ไม่ผ่าน Caching
+func (m *Mediator) register(reqType reflect.Type, handler HandlerFunc, expose remoteHandler, uncached bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.frozen {
+		return ErrFrozen
+	}
+	if _, ok := m.handlers[reqType]; ok {
+		return fmt.Errorf("%w %v", ErrHandlerRegistered, reqType)
+	}
+	m.handlers[reqType] = handler
+	if expose != nil {
+		m.exposed[RequestName(reqType)] = expose
+	}
+	if uncached {
+		m.uncached[reqType] = true
+	}
+	return nil
+}
+
+func (m *Mediator) useFor(reqType reflect.Type, b Behavior) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.frozen {
+		return ErrFrozen
+	}
+	m.typedBehaviors[reqType] = append(m.typedBehaviors[reqType], b)
+	return nil
+}
+
+// pipeline คืน handler ของ reqType ที่ห่อด้วย behavior แล้ว
+func (m *Mediator) pipeline(reqType reflect.Type) (HandlerFunc, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	handler, ok := m.handlers[reqType]
+	if !ok {
+		return nil, false
+	}
+	handler = chain(handler, m.behaviors, m.typedBehaviors[reqType])
+	if !m.uncached[reqType] {
+		return handler, true
+	}
+	return func(ctx context.Context, request any) (any, error) {
+		return handler(withoutCaching(ctx, reqType), request)
+	}, true
+}
+
+type RegisterOption func(*registration)
+
+type registration struct {
+	behaviors []Behavior
+	exposed   bool
+}
+
+// WithBehaviors เพิ่ม behavior เฉพาะ handler นี้ ทำงานในสุด (หลัง behavior จาก Use และ UseBehaviorFor)
+// เช่น mediator.WithBehaviors(mediator.Transaction(transactor))
+func WithBehaviors(bs ...Behavior) RegisterOption {
+	return func(r *registration) {
+		r.behaviors = append(r.behaviors, bs...)
+	}
+}
 
-// Register adds a handler for a specific request type.
-func Register[TRequest any, TResponse any](handler RequestHandler[TRequest, TResponse]) {
+// RegisterHandler adds a handler for a specific request type to m.
+// Registering the same request type twice returns ErrHandlerRegistered.
+func RegisterHandler[TRequest any, TResponse any](m *Mediator, handler RequestHandler[TRequest, TResponse], opts ...RegisterOption) error {
 	// Create a zero value to extract the type.
 	var req TRequest
 	reqType := reflect.TypeOf(req)
 
+	r := &registration{}
+	for _, opt := range opts {
+		opt(r)
+	}
+
 	// Wrap the handler's Handle method in a function that accepts an empty interface.
-	handlers[reqType] = func(ctx context.Context, request interface{}) (interface{}, error) {
+	h := func(ctx context.Context, request any) (any, error) {
 		typedReq, ok := request.(TRequest)
 		if !ok {
 			return nil, errors.New("invalid request type")
 		}
 		return handler.Handle(ctx, typedReq)
 	}
+
+	var expose remoteHandler
+	if r.exposed {
+		expose = func(ctx context.Context, body []byte) (any, error) {
+			var typedReq TRequest
+			if err := json.Unmarshal(body, &typedReq); err != nil {
+				return nil, errs.InputValidationError("invalid request body", err)
+			}
+			return SendRequest[TRequest, TResponse](ctx, m, typedReq)
+		}
+	}
+	return m.register(reqType, chain(h, r.behaviors, nil), expose, false)
 }
 
-// Send dispatches the request to the registered handler.
-func Send[TRequest any, TResponse any](ctx context.Context, req TRequest) (TResponse, error) {
+// SendRequest dispatches the request to the handler registered on m through the behavior pipeline.
+func SendRequest[TRequest any, TResponse any](ctx context.Context, m *Mediator, req TRequest) (TResponse, error) {
 	reqType := reflect.TypeOf(req)
-	handler, ok := handlers[reqType]
+	handler, ok := m.pipeline(reqType)
 	if !ok {
 		var empty TResponse
 		return empty, fmt.Errorf("no handler for request %T", req)
 	}
 
-	result, err := handler(ctx, req)
-	if err != nil {
-		var empty TResponse
-		return empty, err
-	}
+	return typedResult[TResponse](handler(ctx, req))
+}
 
-	typedRes, ok := result.(TResponse)
-	if !ok {
-		var empty TResponse
-		return empty, errors.New("invalid response type")
-	}
+// Register adds a handler for a specific request type to the default mediator.
+//
+// Deprecated: use RegisterHandler with the Mediator from ModuleContext.
+func Register[TRequest any, TResponse any](handler RequestHandler[TRequest, TResponse], opts ...RegisterOption) error {
+	return RegisterHandler(Default(), handler, opts...)
+}
 
-	return typedRes, nil
+// Send dispatches the request to the handler registered on the default mediator.
+//
+// Deprecated: use SendRequest with the Mediator from ModuleContext.
+func Send[TRequest any, TResponse any](ctx context.Context, req TRequest) (TResponse, error) {
+	return SendRequest[TRequest, TResponse](ctx, Default(), req)
 }
@@ -0,0 +1,36 @@
+package mediator
+
+import (
+	"context"
+	"testing"
+)
+
+// useDefault ติดตั้ง m เป็น default mediator แล้วคืนค่าเดิมเมื่อ test จบ
+func useDefault(t *testing.T, m *Mediator) {
+	t.Helper()
+	previous := Default()
+	SetDefault(m)
+	t.Cleanup(func() { SetDefault(previous) })
+}
+
+func TestRegisterAndSendUseDefaultMediator(t *testing.T) {
+	m := New()
+	useDefault(t, m)
+
+	if err := Register[*getCustomerQuery, *getCustomerResult](&creditHandler{credit: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Send[*getCustomerQuery, *getCustomerResult](context.Background(), &getCustomerQuery{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Credit != 100 {
+		t.Fatalf("credit = %d, want 100", res.Credit)
+	}
+
+	// handler ที่ลงทะเบียนผ่าน wrapper อยู่ใน instance ที่ติดตั้งไว้
+	if res, err := SendRequest[*getCustomerQuery, *getCustomerResult](context.Background(), m, &getCustomerQuery{ID: 1}); err != nil || res.Credit != 100 {
+		t.Fatalf("SendRequest on the installed mediator = %v, %v", res, err)
+	}
+}
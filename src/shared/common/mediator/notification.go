@@ -0,0 +1,163 @@
+package mediator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// NotificationHandler รับ notification ภายใน process โดย notification หนึ่ง type มีได้หลาย handler
+// ต่างจาก integration event ตรงที่ไม่ถูกบันทึก ไม่มี retry และทำงานใน context เดียวกับผู้ publish
+type NotificationHandler[TNotification any] interface {
+	Handle(ctx context.Context, notification TNotification) error
+}
+
+// NotificationHandlerFunc ทำให้ฟังก์ชันธรรมดาเป็น NotificationHandler ได้
+type NotificationHandlerFunc[TNotification any] func(ctx context.Context, notification TNotification) error
+
+func (f NotificationHandlerFunc[TNotification]) Handle(ctx context.Context, notification TNotification) error {
+	return f(ctx, notification)
+}
+
+type notificationHandler func(ctx context.Context, notification any) error
+
+// PublishStrategy กำหนดวิธีเรียก handler ทั้งหมดของ notification หนึ่งตัว
+type PublishStrategy func(ctx context.Context, notification any, handlers []notificationHandler) error
+
+// Sequential เรียก handler ทีละตัวตามลำดับที่ลงทะเบียน ทุกตัวจะถูกเรียกแม้ตัวก่อนหน้า error
+// และคืน error ทั้งหมดรวมกันด้วย errors.Join (ค่าเริ่มต้น)
+func Sequential() PublishStrategy {
+	return func(ctx context.Context, notification any, handlers []notificationHandler) error {
+		var errs error
+		for _, h := range handlers {
+			errs = errors.Join(errs, safeHandle(ctx, h, notification))
+		}
+		return errs
+	}
+}
+
+// StopOnFirstError เรียก handler ทีละตัว และหยุดทันทีที่มี handler คืน error
+func StopOnFirstError() PublishStrategy {
+	return func(ctx context.Context, notification any, handlers []notificationHandler) error {
+		for _, h := range handlers {
+			if err := safeHandle(ctx, h, notification); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Parallel เรียกทุก handler พร้อมกัน รอจนครบ แล้วคืน error ทั้งหมดรวมกัน
+// handler ต้องไม่ใช้ transaction ร่วมกัน เพราะ connection ของ transaction ใช้พร้อมกันหลาย goroutine ไม่ได้
+func Parallel() PublishStrategy {
+	return func(ctx context.Context, notification any, handlers []notificationHandler) error {
+		errs := make([]error, len(handlers))
+
+		var wg sync.WaitGroup
+		for i, h := range handlers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = safeHandle(ctx, h, notification)
+			}()
+		}
+		wg.Wait()
+
+		return errors.Join(errs...)
+	}
+}
+
+// safeHandle แปลง panic ของ handler เป็น error เพื่อไม่ให้ handler ตัวอื่นได้รับผลกระทบ
+func safeHandle(ctx context.Context, h notificationHandler, notification any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrHandlerPanic, r)
+		}
+	}()
+	return h(ctx, notification)
+}
+
+// SetPublishStrategy เปลี่ยนวิธีเรียก handler ของทุก notification ที่ไม่ได้กำหนดด้วย SetPublishStrategyFor
+func (m *Mediator) SetPublishStrategy(strategy PublishStrategy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.frozen {
+		return ErrFrozen
+	}
+	m.publishStrategy = strategy
+	return nil
+}
+
+// SetPublishStrategyFor เปลี่ยนวิธีเรียก handler เฉพาะ TNotification ให้ m
+func SetPublishStrategyFor[TNotification any](m *Mediator, strategy PublishStrategy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.frozen {
+		return ErrFrozen
+	}
+	m.notificationStrategies[notificationKey[TNotification]()] = strategy
+	return nil
+}
+
+// notificationKey คือ type ที่ใช้จับคู่ handler กับ notification ทั้งตอนลงทะเบียนและตอน publish
+// ใช้ type parameter ไม่ใช่ type ของค่าที่ส่งมา เพื่อให้ notification ที่เป็น interface หา handler เจอ
+func notificationKey[TNotification any]() reflect.Type {
+	return reflect.TypeFor[TNotification]()
+}
+
+func (m *Mediator) addNotificationHandler(notificationType reflect.Type, handler notificationHandler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.frozen {
+		return ErrFrozen
+	}
+	m.notificationHandlers[notificationType] = append(m.notificationHandlers[notificationType], handler)
+	return nil
+}
+
+func (m *Mediator) notificationPipeline(notificationType reflect.Type) ([]notificationHandler, PublishStrategy) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if strategy, ok := m.notificationStrategies[notificationType]; ok {
+		return m.notificationHandlers[notificationType], strategy
+	}
+	return m.notificationHandlers[notificationType], m.publishStrategy
+}
+
+// RegisterNotificationHandler เพิ่ม handler ของ TNotification ให้ m (ลงทะเบียนได้หลายตัว)
+func RegisterNotificationHandler[TNotification any](m *Mediator, handler NotificationHandler[TNotification]) error {
+	return m.addNotificationHandler(notificationKey[TNotification](), func(ctx context.Context, notification any) error {
+		typed, ok := notification.(TNotification)
+		if !ok {
+			return errors.New("invalid notification type")
+		}
+		return handler.Handle(ctx, typed)
+	})
+}
+
+// PublishNotification ส่ง notification ให้ทุก handler ที่ลงทะเบียนไว้กับ m ด้วย TNotification เดียวกันตาม PublishStrategy
+// ถ้าไม่มี handler เลยจะคืน nil
+func PublishNotification[TNotification any](ctx context.Context, m *Mediator, notification TNotification) error {
+	handlers, strategy := m.notificationPipeline(notificationKey[TNotification]())
+	if len(handlers) == 0 {
+		return nil
+	}
+	return strategy(ctx, notification, handlers)
+}
+
+// RegisterNotification เพิ่ม handler ของ TNotification ให้ default mediator
+func RegisterNotification[TNotification any](handler NotificationHandler[TNotification]) error {
+	return RegisterNotificationHandler(Default(), handler)
+}
+
+// Publish ส่ง notification ผ่าน default mediator ที่ Application ติดตั้งไว้ (ดู PublishNotification)
+func Publish[TNotification any](ctx context.Context, notification TNotification) error {
+	return PublishNotification(ctx, Default(), notification)
+}
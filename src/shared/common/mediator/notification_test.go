@@ -0,0 +1,226 @@
+package mediator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+type orderCanceled struct {
+	OrderID int64
+}
+
+// auditable เป็น notification แบบ interface ที่หลาย type ใช้ร่วมกัน
+type auditable interface {
+	AuditMessage() string
+}
+
+func (n *orderCanceled) AuditMessage() string {
+	return "order canceled"
+}
+
+// recorder เก็บชื่อ handler ที่ถูกเรียกตามลำดับ
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) handler(name string, err error) NotificationHandlerFunc[*orderCanceled] {
+	return func(ctx context.Context, n *orderCanceled) error {
+		r.mu.Lock()
+		r.calls = append(r.calls, name)
+		r.mu.Unlock()
+		return err
+	}
+}
+
+func TestPublishNotificationFansOutToAllHandlers(t *testing.T) {
+	errFirst := errors.New("first failed")
+	errThird := errors.New("third failed")
+
+	tests := []struct {
+		name      string
+		strategy  PublishStrategy
+		ordered   bool
+		wantCalls []string
+		wantErrs  []error
+	}{
+		{
+			name:      "sequential runs every handler and joins errors",
+			strategy:  Sequential(),
+			ordered:   true,
+			wantCalls: []string{"first", "second", "third"},
+			wantErrs:  []error{errFirst, errThird},
+		},
+		{
+			name:      "stop on first error",
+			strategy:  StopOnFirstError(),
+			ordered:   true,
+			wantCalls: []string{"first"},
+			wantErrs:  []error{errFirst},
+		},
+		{
+			name:      "parallel runs every handler and joins errors",
+			strategy:  Parallel(),
+			wantCalls: []string{"first", "second", "third"},
+			wantErrs:  []error{errFirst, errThird},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			rec := &recorder{}
+			if err := m.SetPublishStrategy(tt.strategy); err != nil {
+				t.Fatal(err)
+			}
+			for _, h := range []NotificationHandlerFunc[*orderCanceled]{
+				rec.handler("first", errFirst),
+				rec.handler("second", nil),
+				rec.handler("third", errThird),
+			} {
+				if err := RegisterNotificationHandler[*orderCanceled](m, h); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := PublishNotification(context.Background(), m, &orderCanceled{OrderID: 1})
+
+			for _, want := range tt.wantErrs {
+				if !errors.Is(err, want) {
+					t.Errorf("error = %v, want it to wrap %v", err, want)
+				}
+			}
+			if len(rec.calls) != len(tt.wantCalls) {
+				t.Fatalf("calls = %v, want %v", rec.calls, tt.wantCalls)
+			}
+			if tt.ordered {
+				for i := range tt.wantCalls {
+					if rec.calls[i] != tt.wantCalls[i] {
+						t.Fatalf("calls = %v, want %v", rec.calls, tt.wantCalls)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPublishNotificationRecoversHandlerPanic(t *testing.T) {
+	m := New()
+	rec := &recorder{}
+	_ = RegisterNotificationHandler[*orderCanceled](m, NotificationHandlerFunc[*orderCanceled](func(ctx context.Context, n *orderCanceled) error {
+		panic("boom")
+	}))
+	_ = RegisterNotificationHandler[*orderCanceled](m, rec.handler("after panic", nil))
+
+	err := PublishNotification(context.Background(), m, &orderCanceled{OrderID: 1})
+	if !errors.Is(err, ErrHandlerPanic) {
+		t.Fatalf("error = %v, want %v", err, ErrHandlerPanic)
+	}
+	if len(rec.calls) != 1 {
+		t.Fatalf("handler after panic was not called: %v", rec.calls)
+	}
+}
+
+func TestPublishNotificationWithoutHandlers(t *testing.T) {
+	if err := PublishNotification(context.Background(), New(), &orderCanceled{}); err != nil {
+		t.Fatalf("error = %v, want nil", err)
+	}
+}
+
+// handler ที่ลงทะเบียนด้วย interface ต้องได้รับ notification ที่ publish ผ่าน interface เดียวกัน
+func TestPublishNotificationMatchesInterfaceType(t *testing.T) {
+	m := New()
+
+	var got []string
+	err := RegisterNotificationHandler[auditable](m, NotificationHandlerFunc[auditable](func(ctx context.Context, n auditable) error {
+		got = append(got, n.AuditMessage())
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var n auditable = &orderCanceled{OrderID: 1}
+	if err := PublishNotification(context.Background(), m, n); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != "order canceled" {
+		t.Fatalf("interface handler calls = %v, want 1", got)
+	}
+
+	// publish ด้วย type จริงจับคู่กับ handler ของ type จริงเท่านั้น
+	if err := PublishNotification(context.Background(), m, &orderCanceled{OrderID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("interface handler got a concrete-typed publish: %v", got)
+	}
+}
+
+func TestSetPublishStrategyForOverridesDefault(t *testing.T) {
+	errFirst := errors.New("first failed")
+
+	m := New()
+	rec := &recorder{}
+	if err := SetPublishStrategyFor[*orderCanceled](m, StopOnFirstError()); err != nil {
+		t.Fatal(err)
+	}
+	_ = RegisterNotificationHandler[*orderCanceled](m, rec.handler("first", errFirst))
+	_ = RegisterNotificationHandler[*orderCanceled](m, rec.handler("second", nil))
+
+	var auditCalls int
+	_ = RegisterNotificationHandler[auditable](m, NotificationHandlerFunc[auditable](func(ctx context.Context, n auditable) error {
+		auditCalls++
+		return errFirst
+	}))
+	_ = RegisterNotificationHandler[auditable](m, NotificationHandlerFunc[auditable](func(ctx context.Context, n auditable) error {
+		auditCalls++
+		return nil
+	}))
+
+	if err := PublishNotification(context.Background(), m, &orderCanceled{}); !errors.Is(err, errFirst) {
+		t.Fatalf("error = %v, want %v", err, errFirst)
+	}
+	if len(rec.calls) != 1 {
+		t.Fatalf("calls = %v, want only first", rec.calls)
+	}
+
+	// notification type อื่นยังใช้ Sequential ซึ่งเป็นค่าเริ่มต้น
+	var n auditable = &orderCanceled{}
+	_ = PublishNotification(context.Background(), m, n)
+	if auditCalls != 2 {
+		t.Fatalf("audit handler calls = %d, want 2", auditCalls)
+	}
+}
+
+func TestNotificationRegistrationAfterFreeze(t *testing.T) {
+	m := New()
+	m.Freeze()
+
+	if err := RegisterNotificationHandler[*orderCanceled](m, (&recorder{}).handler("late", nil)); !errors.Is(err, ErrFrozen) {
+		t.Errorf("RegisterNotificationHandler error = %v, want %v", err, ErrFrozen)
+	}
+	if err := SetPublishStrategyFor[*orderCanceled](m, Parallel()); !errors.Is(err, ErrFrozen) {
+		t.Errorf("SetPublishStrategyFor error = %v, want %v", err, ErrFrozen)
+	}
+}
+
+func TestPublishUsesDefaultMediator(t *testing.T) {
+	useDefault(t, New())
+	rec := &recorder{}
+	if err := RegisterNotification[*orderCanceled](rec.handler("first", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterNotificationHandler[*orderCanceled](Default(), rec.handler("second", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Publish(context.Background(), &orderCanceled{OrderID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.calls) != 2 {
+		t.Fatalf("calls = %v, want first and second", rec.calls)
+	}
+}
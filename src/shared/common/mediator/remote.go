@@ -0,0 +1,175 @@
+package mediator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-mma/shared/common/auth"
+	"go-mma/shared/common/errs"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var ErrRequestNotExposed = errors.New("request is not exposed")
+
+// Transport ส่ง request ไปให้ handler ที่อยู่ใน process อื่น แล้ว decode ผลลัพธ์ลงใน response
+// name คือชื่อจาก RequestName ซึ่งทั้งสองฝั่งได้จาก type ใน shared/contract ตัวเดียวกัน
+type Transport interface {
+	Send(ctx context.Context, name string, request any, response any) error
+}
+
+// RequestName คืนชื่อของ request type ที่ใช้ระบุ handler ข้าม process เช่น "customercontract.ReserveCreditCommand"
+func RequestName(reqType reflect.Type) string {
+	for reqType.Kind() == reflect.Pointer {
+		reqType = reqType.Elem()
+	}
+	return reqType.String()
+}
+
+// RegisterRemote ลงทะเบียน handler ที่ส่ง TRequest ต่อไปยัง process อื่นผ่าน t
+// ผู้ส่งยังเรียก SendRequest เหมือนเดิม จึงย้ายโมดูลออกไปได้โดยไม่ต้องแก้ handler ที่เรียกใช้
+// request ที่ส่งออกไปไม่อยู่ใน transaction ของผู้ส่ง ถ้าผู้ส่ง rollback ฝั่งปลายทางจะไม่ rollback ตาม
+// ผลลัพธ์ไม่ถูกเก็บด้วย Caching เพราะการ invalidate เกิดใน process ปลายทาง process นี้จะได้ข้อมูลเก่า
+func RegisterRemote[TRequest any, TResponse any](m *Mediator, t Transport, opts ...RegisterOption) error {
+	var req TRequest
+	reqType := reflect.TypeOf(req)
+	name := RequestName(reqType)
+
+	r := &registration{}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	h := func(ctx context.Context, request any) (any, error) {
+		var resp TResponse
+		if err := t.Send(ctx, name, request, &resp); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return m.register(reqType, chain(h, r.behaviors, nil), nil, true)
+}
+
+// remoteHandler decode body เป็น request type ของตัวเอง แล้วส่งผ่าน pipeline ของ mediator
+type remoteHandler func(ctx context.Context, body []byte) (any, error)
+
+// Exposed เปิดให้ Server รับ request นี้จาก process อื่นได้
+// เช่น mediator.RegisterHandler(m, handler, mediator.Exposed())
+func Exposed() RegisterOption {
+	return func(r *registration) {
+		r.exposed = true
+	}
+}
+
+// Server คือฝั่งรับของ Transport ใช้ต่อกับ HTTP endpoint ของ application
+// request ทำงานผ่าน behavior ทั้งหมดของ mediator เหมือนเรียกภายใน process
+type Server struct {
+	m *Mediator
+}
+
+func NewServer(m *Mediator) *Server {
+	return &Server{m: m}
+}
+
+// Handle ส่ง body ให้ handler ที่ลงทะเบียนด้วย Exposed ตามชื่อ request
+func (s *Server) Handle(ctx context.Context, name string, body []byte) (any, error) {
+	s.m.mu.RLock()
+	handler, ok := s.m.exposed[name]
+	s.m.mu.RUnlock()
+
+	if !ok {
+		return nil, errs.ResourceNotFoundError(fmt.Sprintf("request %s not found", name), ErrRequestNotExposed)
+	}
+	return handler(ctx, body)
+}
+
+type httpTransport struct {
+	baseURL string
+	client  *http.Client
+	secret  string
+}
+
+type HTTPTransportOption func(*httpTransport)
+
+// WithHTTPClient กำหนด http.Client ที่ใช้ส่ง request (ค่าเริ่มต้น timeout 10 วินาที)
+func WithHTTPClient(client *http.Client) HTTPTransportOption {
+	return func(t *httpTransport) {
+		t.client = client
+	}
+}
+
+// WithServiceSecret ส่ง secret ใน header X-Service-Secret ปลายทางจึงเชื่อ header ตัวตนของ principal ที่ส่งไป
+func WithServiceSecret(secret string) HTTPTransportOption {
+	return func(t *httpTransport) {
+		t.secret = secret
+	}
+}
+
+// NewHTTPTransport ส่ง request เป็น JSON ด้วย POST {baseURL}/{name}
+// principal ใน context จะถูกส่งต่อใน header เดียวกับที่ API gateway ใช้
+// error ที่ปลายทางตอบกลับมาเป็น errs.AppError จะคืนเป็น type เดิม
+func NewHTTPTransport(baseURL string, opts ...HTTPTransportOption) Transport {
+	t := &httpTransport{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+func (t *httpTransport) Send(ctx context.Context, name string, request any, response any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errs.InputValidationError(fmt.Sprintf("failed to encode request %s", name), err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/"+url.PathEscape(name), bytes.NewReader(body))
+	if err != nil {
+		return errs.OperationFailedError(fmt.Sprintf("failed to create request %s", name), err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.secret != "" {
+		req.Header.Set(auth.HeaderServiceSecret, t.secret)
+	}
+	if p, ok := auth.PrincipalFromContext(ctx); ok {
+		req.Header.Set(auth.HeaderUserID, p.UserID)
+		req.Header.Set(auth.HeaderUserRoles, strings.Join(p.Roles, ","))
+		if p.TenantID != "" {
+			req.Header.Set(auth.HeaderTenantID, p.TenantID)
+		}
+	}
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return errs.ServiceDependencyError(fmt.Sprintf("failed to send request %s", name), err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errs.ServiceDependencyError(fmt.Sprintf("failed to read response of %s", name), err)
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		var appErr errs.AppError
+		if err := json.Unmarshal(data, &appErr); err != nil || appErr.Type == "" {
+			return errs.ServiceDependencyError(fmt.Sprintf("request %s failed with status %d", name, res.StatusCode))
+		}
+		return &appErr
+	}
+
+	if err := json.Unmarshal(data, response); err != nil {
+		return errs.ServiceDependencyError(fmt.Sprintf("failed to decode response of %s", name), err)
+	}
+	return nil
+}
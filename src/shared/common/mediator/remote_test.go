@@ -0,0 +1,105 @@
+package mediator
+
+import (
+	"context"
+	"encoding/json"
+	"go-mma/shared/common/cache"
+	"testing"
+	"time"
+)
+
+type getCustomerQuery struct {
+	ID int64 `json:"id"`
+}
+
+func (q *getCustomerQuery) CacheKey() string        { return "customer" }
+func (q *getCustomerQuery) CacheTTL() time.Duration { return time.Minute }
+
+type getCustomerResult struct {
+	Credit int `json:"credit"`
+}
+
+// loopbackTransport ส่ง request ไปที่ Server ของอีก Mediator หนึ่งโดยตรง แทน HTTP
+type loopbackTransport struct {
+	server *Server
+	sent   int
+}
+
+func (t *loopbackTransport) Send(ctx context.Context, name string, request any, response any) error {
+	t.sent++
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	res, err := t.server.Handle(ctx, name, body)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+type creditHandler struct {
+	credit int
+}
+
+func (h *creditHandler) Handle(ctx context.Context, q *getCustomerQuery) (*getCustomerResult, error) {
+	return &getCustomerResult{Credit: h.credit}, nil
+}
+
+// process ที่เรียกข้าม process ต้องไม่ cache ผลลัพธ์ เพราะไม่ได้รับการ invalidate จากปลายทาง
+func TestRegisterRemoteSkipsCaching(t *testing.T) {
+	owner := New()
+	handler := &creditHandler{credit: 100}
+	if err := RegisterHandler[*getCustomerQuery, *getCustomerResult](owner, handler, Exposed()); err != nil {
+		t.Fatal(err)
+	}
+
+	caller := New()
+	if err := caller.Use(Caching(cache.NewLRU(10))); err != nil {
+		t.Fatal(err)
+	}
+	transport := &loopbackTransport{server: NewServer(owner)}
+	if err := RegisterRemote[*getCustomerQuery, *getCustomerResult](caller, transport); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err := SendRequest[*getCustomerQuery, *getCustomerResult](ctx, caller, &getCustomerQuery{ID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	handler.credit = 50
+	res, err := SendRequest[*getCustomerQuery, *getCustomerResult](ctx, caller, &getCustomerQuery{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Credit != 50 || transport.sent != 2 {
+		t.Fatalf("credit = %d after %d send(s), want fresh credit 50 after 2 sends", res.Credit, transport.sent)
+	}
+}
+
+func TestLocalHandlerIsCached(t *testing.T) {
+	m := New()
+	if err := m.Use(Caching(cache.NewLRU(10))); err != nil {
+		t.Fatal(err)
+	}
+	handler := &creditHandler{credit: 100}
+	if err := RegisterHandler[*getCustomerQuery, *getCustomerResult](m, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	_, _ = SendRequest[*getCustomerQuery, *getCustomerResult](ctx, m, &getCustomerQuery{ID: 1})
+	handler.credit = 50
+	res, err := SendRequest[*getCustomerQuery, *getCustomerResult](ctx, m, &getCustomerQuery{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Credit != 100 {
+		t.Fatalf("credit = %d, want cached 100", res.Credit)
+	}
+}
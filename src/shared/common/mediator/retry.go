@@ -0,0 +1,44 @@
+package mediator
+
+import (
+	"context"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/logger"
+	"math/rand/v2"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// conflictBackoff คือเวลารอพื้นฐานก่อนลองใหม่ เพิ่มขึ้นตามจำนวนครั้งและสุ่มเพื่อไม่ให้ชนกันซ้ำ
+const conflictBackoff = 20 * time.Millisecond
+
+// RetryOnConflict เรียก handler ใหม่ทั้งหมดเมื่อได้ errs.ErrConcurrency จนครบ maxAttempts ครั้ง (รวมครั้งแรก)
+// ต้องอยู่นอก Transaction เพื่อให้แต่ละครั้งเปิด transaction ใหม่และอ่านข้อมูลล่าสุด
+// เช่น mediator.WithBehaviors(mediator.RetryOnConflict(3), mediator.Transaction(transactor))
+func RetryOnConflict(maxAttempts int) Behavior {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request any) (any, error) {
+			for attempt := 1; ; attempt++ {
+				res, err := next(ctx, request)
+				if err == nil || attempt >= maxAttempts || errs.GetErrorType(err) != errs.ErrConcurrency {
+					return res, err
+				}
+
+				logger.Log.Warn("retrying request after concurrency conflict",
+					zap.String("request", requestName(request)),
+					zap.Int("attempt", attempt),
+					zap.Error(err),
+				)
+
+				backoff := time.Duration(attempt) * conflictBackoff
+				backoff += rand.N(backoff)
+				select {
+				case <-ctx.Done():
+					return nil, err
+				case <-time.After(backoff):
+				}
+			}
+		}
+	}
+}
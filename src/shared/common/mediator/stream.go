@@ -0,0 +1,105 @@
+package mediator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-mma/shared/common/logger"
+	"iter"
+	"reflect"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+)
+
+// StreamHandler คืนผลลัพธ์ทีละรายการ ใช้กับงานที่ข้อมูลมีจำนวนมาก เช่น export
+// error ที่ yield ออกมาถือเป็นจุดสิ้นสุดของ stream ผู้อ่านไม่ควรอ่านต่อ
+type StreamHandler[TRequest any, TItem any] interface {
+	Handle(ctx context.Context, request TRequest) iter.Seq2[TItem, error]
+}
+
+// RegisterStreamHandler adds a stream handler for a specific request type to m.
+// behavior ของ mediator ครอบเฉพาะขั้นเปิด stream (เช่น ตรวจสิทธิ์และ validate) ไม่ได้ครอบการอ่านแต่ละรายการ
+// จึงไม่ควรใช้ร่วมกับ Transaction หรือ Caching
+func RegisterStreamHandler[TRequest any, TItem any](m *Mediator, handler StreamHandler[TRequest, TItem], opts ...RegisterOption) error {
+	var req TRequest
+	reqType := reflect.TypeOf(req)
+
+	r := &registration{}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	h := func(ctx context.Context, request any) (any, error) {
+		typedReq, ok := request.(TRequest)
+		if !ok {
+			return nil, errors.New("invalid request type")
+		}
+		return handler.Handle(ctx, typedReq), nil
+	}
+	return m.register(reqType, chain(h, r.behaviors, nil), nil, false)
+}
+
+// StreamRequest ส่ง request ให้ stream handler ที่ลงทะเบียนไว้กับ m
+// error จากการเปิด stream จะถูก yield เป็นรายการแรก
+// เมื่อ ctx ถูก cancel จะ yield ctx.Err() แล้วหยุด และ panic ใน handler จะกลายเป็น ErrHandlerPanic
+func StreamRequest[TRequest any, TItem any](ctx context.Context, m *Mediator, req TRequest) iter.Seq2[TItem, error] {
+	return func(yield func(TItem, error) bool) {
+		var empty TItem
+
+		reqType := reflect.TypeOf(req)
+		handler, ok := m.pipeline(reqType)
+		if !ok {
+			yield(empty, fmt.Errorf("no handler for request %T", req))
+			return
+		}
+
+		res, err := handler(ctx, req)
+		if err != nil {
+			yield(empty, err)
+			return
+		}
+		seq, ok := res.(iter.Seq2[TItem, error])
+		if !ok {
+			yield(empty, fmt.Errorf("handler for request %T is not a stream of %T", req, empty))
+			return
+		}
+
+		// panic ที่เกิดใน yield เป็นของผู้อ่าน ไม่ใช่ของ handler จึงปล่อยต่อไป
+		inYield := false
+		defer func() {
+			if r := recover(); r != nil {
+				if inYield {
+					panic(r)
+				}
+				logger.Log.Error("stream handler panic",
+					zap.String("request", requestName(req)),
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				yield(empty, fmt.Errorf("%w: %v", ErrHandlerPanic, r))
+			}
+		}()
+
+		for item, err := range seq {
+			if err == nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					item, err = empty, ctxErr
+				}
+			}
+
+			inYield = true
+			more := yield(item, err)
+			inYield = false
+
+			if !more || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Stream sends the request to the stream handler registered on the default mediator (see StreamRequest).
+func Stream[TRequest any, TItem any](ctx context.Context, req TRequest) iter.Seq2[TItem, error] {
+	return StreamRequest[TRequest, TItem](ctx, Default(), req)
+}
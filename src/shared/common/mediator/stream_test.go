@@ -0,0 +1,41 @@
+package mediator
+
+import (
+	"context"
+	"iter"
+	"testing"
+)
+
+type countQuery struct {
+	N int
+}
+
+type countHandler struct{}
+
+func (h countHandler) Handle(ctx context.Context, q *countQuery) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for i := 1; i <= q.N; i++ {
+			if !yield(i, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestStreamUsesDefaultMediator(t *testing.T) {
+	useDefault(t, New())
+	if err := RegisterStreamHandler[*countQuery, int](Default(), countHandler{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []int
+	for item, err := range Stream[*countQuery, int](context.Background(), &countQuery{N: 3}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, item)
+	}
+	if len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Fatalf("items = %v, want [1 2 3]", got)
+	}
+}
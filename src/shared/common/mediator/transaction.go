@@ -0,0 +1,152 @@
+package mediator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-mma/shared/common/domain"
+	"go-mma/shared/common/storage/sqldb/transactor"
+	"sync"
+)
+
+var (
+	ErrNoAggregateTracker = errors.New("aggregate can only be tracked within mediator.Transaction")
+	ErrEventCascadeLimit  = errors.New("in-transaction domain event handlers kept raising new events")
+)
+
+// maxInTransactionRounds จำกัดจำนวนรอบที่ handler ภายใน transaction raise event ใหม่ต่อกัน กันวนไม่รู้จบ
+const maxInTransactionRounds = 10
+
+// EventSource คือ aggregate ที่เก็บ domain event ไว้รอ dispatch
+type EventSource interface {
+	PullDomainEvents() []domain.DomainEvent
+}
+
+type TransactionOption func(*transactionConfig)
+
+type transactionConfig struct {
+	beforeCommit domain.DomainEventDispatcher
+	afterCommit  domain.DomainEventDispatcher
+}
+
+// DispatchBeforeCommit ให้ Transaction dispatch domain event ของ aggregate ที่ถูก TrackAggregate
+// ไปยัง handler ที่ลงทะเบียนด้วย domain.InTransaction หลัง handler ทำงานสำเร็จแต่ก่อน commit
+func DispatchBeforeCommit(dispatcher domain.DomainEventDispatcher) TransactionOption {
+	return func(c *transactionConfig) {
+		c.beforeCommit = dispatcher
+	}
+}
+
+// DispatchAfterCommit ให้ Transaction ดึง domain event จาก aggregate ที่ถูก TrackAggregate
+// แล้ว dispatch ผ่าน post-commit hook เมื่อ commit สำเร็จ
+func DispatchAfterCommit(dispatcher domain.DomainEventDispatcher) TransactionOption {
+	return func(c *transactionConfig) {
+		c.afterCommit = dispatcher
+	}
+}
+
+// Transaction เปิด transaction ครอบ handler ถ้า handler คืน error จะ rollback ทั้งหมด
+// handler ลงทะเบียน post-commit hook ได้ด้วย transactor.RegisterPostCommitHook(ctx, hook)
+// ถ้าถูกเรียกซ้อนภายใน transaction อื่น จะเป็น nested transaction ตาม strategy ของ transactor
+//
+// ลำดับการ dispatch domain event เมื่อ handler คืนค่าสำเร็จ:
+//  1. ดึง event จาก aggregate ตามลำดับที่ถูก TrackAggregate แล้วส่งให้ handler ของ DispatchBeforeCommit
+//     ถ้า handler เหล่านี้ raise event ใหม่ (หรือ track aggregate เพิ่ม) จะ dispatch ต่อเป็นรอบถัดไป
+//     ไม่เกิน maxInTransactionRounds รอบ
+//  2. ถ้ามี error ใดๆ ในข้อ 1 จะหยุดทันที rollback และคืน error นั้นให้ผู้เรียก handler หลัง commit จะไม่ถูกเรียก
+//  3. commit แล้วส่ง event ทั้งหมด (รวมที่เกิดในข้อ 1) ให้ handler ของ DispatchAfterCommit ผ่าน post-commit hook
+//     error ในขั้นนี้ถูก log เท่านั้น เพราะข้อมูลถูก commit ไปแล้ว
+func Transaction(t transactor.Transactor, opts ...TransactionOption) Behavior {
+	cfg := &transactionConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, request any) (any, error) {
+			var res any
+			err := t.WithinTransaction(ctx, func(ctx context.Context, registerPostCommitHook func(transactor.PostCommitHook)) error {
+				tracker := &aggregateTracker{}
+				ctx = context.WithValue(ctx, aggregateTrackerKey{}, tracker)
+
+				var err error
+				if res, err = next(ctx, request); err != nil {
+					return err
+				}
+
+				events, err := cfg.dispatchBeforeCommit(ctx, tracker)
+				if err != nil {
+					return err
+				}
+
+				if cfg.afterCommit != nil && len(events) > 0 {
+					registerPostCommitHook(func(ctx context.Context) error {
+						return cfg.afterCommit.Dispatch(ctx, events)
+					})
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			return res, nil
+		}
+	}
+}
+
+// dispatchBeforeCommit คืน event ทั้งหมดที่ถูก dispatch เพื่อส่งต่อให้ handler หลัง commit
+func (c *transactionConfig) dispatchBeforeCommit(ctx context.Context, tracker *aggregateTracker) ([]domain.DomainEvent, error) {
+	events := tracker.pull()
+	if c.beforeCommit == nil {
+		return events, nil
+	}
+
+	pending := events
+	for round := 0; len(pending) > 0; round++ {
+		if round == maxInTransactionRounds {
+			return nil, fmt.Errorf("%w: stopped after %d rounds", ErrEventCascadeLimit, maxInTransactionRounds)
+		}
+		if err := c.beforeCommit.DispatchInTransaction(ctx, pending); err != nil {
+			return nil, err
+		}
+		pending = tracker.pull()
+		events = append(events, pending...)
+	}
+	return events, nil
+}
+
+type aggregateTrackerKey struct{}
+
+type aggregateTracker struct {
+	mu         sync.Mutex
+	aggregates []EventSource
+}
+
+func (t *aggregateTracker) add(agg EventSource) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.aggregates = append(t.aggregates, agg)
+}
+
+func (t *aggregateTracker) pull() []domain.DomainEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var events []domain.DomainEvent
+	for _, agg := range t.aggregates {
+		events = append(events, agg.PullDomainEvents()...)
+	}
+	return events
+}
+
+// TrackAggregate บอก Transaction ให้ dispatch domain event ของ agg ก่อนและหลัง commit
+// event ที่ถูกเพิ่มหลังเรียก TrackAggregate (แต่ก่อน handler จบ) ก็จะถูก dispatch ด้วย
+func TrackAggregate(ctx context.Context, agg EventSource) error {
+	tracker, ok := ctx.Value(aggregateTrackerKey{}).(*aggregateTracker)
+	if !ok {
+		return ErrNoAggregateTracker
+	}
+	tracker.add(agg)
+	return nil
+}
@@ -0,0 +1,303 @@
+package mediator
+
+import (
+	"context"
+	"errors"
+	"go-mma/shared/common/domain"
+	"go-mma/shared/common/storage/sqldb/transactor"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeTransactor จำลอง transactor: transaction ซ้อนส่ง hook ให้ชั้นนอก และ hook รันหลัง commit ของชั้นนอกสุดเท่านั้น
+// error ของ hook ถูกเก็บไว้ใน hookErrs แทนการ log เหมือน transactor จริง
+type fakeTransactor struct {
+	mu       sync.Mutex
+	log      []string
+	hookErrs []error
+}
+
+type fakeTxKey struct{}
+
+type fakeTx struct {
+	hooks []transactor.PostCommitHook
+}
+
+func (t *fakeTransactor) record(s string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.log = append(t.log, s)
+}
+
+func (t *fakeTransactor) WithinTransaction(ctx context.Context, txFunc func(ctxWithTx context.Context, registerPostCommitHook func(transactor.PostCommitHook)) error) error {
+	parent, nested := ctx.Value(fakeTxKey{}).(*fakeTx)
+	if nested {
+		t.record("SAVEPOINT")
+	} else {
+		t.record("BEGIN")
+	}
+
+	tx := &fakeTx{}
+	register := func(hook transactor.PostCommitHook) {
+		tx.hooks = append(tx.hooks, hook)
+	}
+	if err := txFunc(context.WithValue(ctx, fakeTxKey{}, tx), register); err != nil {
+		if nested {
+			t.record("ROLLBACK TO SAVEPOINT")
+		} else {
+			t.record("ROLLBACK")
+		}
+		return err
+	}
+
+	if nested {
+		t.record("RELEASE SAVEPOINT")
+		parent.hooks = append(parent.hooks, tx.hooks...)
+		return nil
+	}
+
+	t.record("COMMIT")
+	for _, hook := range tx.hooks {
+		if err := hook(ctx); err != nil {
+			t.hookErrs = append(t.hookErrs, err)
+		}
+	}
+	return nil
+}
+
+func (t *fakeTransactor) Drain(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+type trackedAggregate struct {
+	domain.Aggregate
+}
+
+func (a *trackedAggregate) raise(names ...domain.EventName) {
+	for _, name := range names {
+		a.AddDomainEvent(domain.BaseDomainEvent{Name: name})
+	}
+}
+
+// raising คืน handler ที่ track aggregate ใหม่แล้ว raise events ทำให้เกิด event รอบถัดไป
+func raising(tr *fakeTransactor, phase string, events ...domain.EventName) domain.DomainEventHandlerFunc {
+	return func(ctx context.Context, event domain.DomainEvent) error {
+		tr.record(phase + " " + string(event.EventName()))
+		if len(events) == 0 {
+			return nil
+		}
+		agg := &trackedAggregate{}
+		agg.raise(events...)
+		return TrackAggregate(ctx, agg)
+	}
+}
+
+func failing(tr *fakeTransactor, phase string, err error) domain.DomainEventHandlerFunc {
+	return func(ctx context.Context, event domain.DomainEvent) error {
+		tr.record(phase + " " + string(event.EventName()))
+		return err
+	}
+}
+
+// handlerRaising คืน handler ของ request ที่ track aggregate และ raise events
+func handlerRaising(events ...domain.EventName) HandlerFunc {
+	return func(ctx context.Context, request any) (any, error) {
+		agg := &trackedAggregate{}
+		if err := TrackAggregate(ctx, agg); err != nil {
+			return nil, err
+		}
+		agg.raise(events...)
+		return "ok", nil
+	}
+}
+
+func transactionWith(tr *fakeTransactor, d domain.DomainEventDispatcher, next HandlerFunc) HandlerFunc {
+	return Transaction(tr, DispatchBeforeCommit(d), DispatchAfterCommit(d))(next)
+}
+
+func assertLog(t *testing.T, tr *fakeTransactor, want ...string) {
+	t.Helper()
+	if got := tr.log; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("log = %q, want %q", got, want)
+	}
+}
+
+// event ที่ handler ภายใน transaction raise จะถูก dispatch เป็นรอบถัดไปตามลำดับ ก่อน commit
+// และ handler หลัง commit ได้รับ event ทุกตัวตามลำดับเดียวกัน
+func TestTransactionDispatchesCascadeRoundsInOrder(t *testing.T) {
+	tr := &fakeTransactor{}
+	d := domain.NewSimpleDomainEventDispatcher()
+	d.Register("A", raising(tr, "before", "C"), domain.InTransaction())
+	d.Register("B", raising(tr, "before", "D"), domain.InTransaction())
+	d.Register("C", raising(tr, "before"), domain.InTransaction())
+	d.Register("D", raising(tr, "before"), domain.InTransaction())
+	for _, name := range []domain.EventName{"A", "B", "C", "D"} {
+		d.Register(name, raising(tr, "after"))
+	}
+
+	res, err := transactionWith(tr, d, handlerRaising("A", "B"))(context.Background(), "request")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != "ok" {
+		t.Fatalf("response = %v, want ok", res)
+	}
+	assertLog(t, tr,
+		"BEGIN",
+		"before A", "before B",
+		"before C", "before D",
+		"COMMIT",
+		"after A", "after B", "after C", "after D",
+	)
+}
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	errBefore := errors.New("in-transaction handler failed")
+
+	tests := []struct {
+		name    string
+		next    HandlerFunc
+		wantErr error
+		wantLog []string
+	}{
+		{
+			name: "request handler error skips dispatch",
+			next: func(ctx context.Context, request any) (any, error) {
+				agg := &trackedAggregate{}
+				agg.raise("A")
+				_ = TrackAggregate(ctx, agg)
+				return nil, errHandler
+			},
+			wantErr: errHandler,
+			wantLog: []string{"BEGIN", "ROLLBACK"},
+		},
+		{
+			name:    "in-transaction handler error stops dispatch and skips after-commit handlers",
+			next:    handlerRaising("A", "B", "C"),
+			wantErr: errBefore,
+			wantLog: []string{"BEGIN", "before A", "before B", "ROLLBACK"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &fakeTransactor{}
+			d := domain.NewSimpleDomainEventDispatcher()
+			d.Register("A", raising(tr, "before"), domain.InTransaction())
+			d.Register("B", failing(tr, "before", errBefore), domain.InTransaction())
+			d.Register("C", raising(tr, "before"), domain.InTransaction())
+			for _, name := range []domain.EventName{"A", "B", "C"} {
+				d.Register(name, raising(tr, "after"))
+			}
+
+			res, err := transactionWith(tr, d, tt.next)(context.Background(), "request")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if res != nil {
+				t.Fatalf("response = %v, want nil", res)
+			}
+			assertLog(t, tr, tt.wantLog...)
+		})
+	}
+}
+
+func TestTransactionStopsEndlessEventCascade(t *testing.T) {
+	tr := &fakeTransactor{}
+	d := domain.NewSimpleDomainEventDispatcher()
+	d.Register("A", raising(tr, "before", "A"), domain.InTransaction())
+	d.Register("A", raising(tr, "after"))
+
+	_, err := transactionWith(tr, d, handlerRaising("A"))(context.Background(), "request")
+	if !errors.Is(err, ErrEventCascadeLimit) {
+		t.Fatalf("error = %v, want %v", err, ErrEventCascadeLimit)
+	}
+
+	want := []string{"BEGIN"}
+	for range maxInTransactionRounds {
+		want = append(want, "before A")
+	}
+	assertLog(t, tr, append(want, "ROLLBACK")...)
+}
+
+// error ของ handler หลัง commit ไม่ย้อนกลับไปหาผู้เรียก เพราะข้อมูลถูก commit ไปแล้ว
+func TestTransactionAfterCommitErrorIsNotReturned(t *testing.T) {
+	errAfter := errors.New("after-commit handler failed")
+
+	tr := &fakeTransactor{}
+	d := domain.NewSimpleDomainEventDispatcher()
+	d.Register("A", failing(tr, "after", errAfter))
+
+	res, err := transactionWith(tr, d, handlerRaising("A"))(context.Background(), "request")
+	if err != nil {
+		t.Fatalf("error = %v, want nil", err)
+	}
+	if res != "ok" {
+		t.Fatalf("response = %v, want ok", res)
+	}
+	assertLog(t, tr, "BEGIN", "COMMIT", "after A")
+	if len(tr.hookErrs) != 1 || !errors.Is(tr.hookErrs[0], errAfter) {
+		t.Fatalf("hook errors = %v, want %v", tr.hookErrs, errAfter)
+	}
+}
+
+func TestNestedTransactionDispatch(t *testing.T) {
+	errOuter := errors.New("outer failed")
+
+	tests := []struct {
+		name     string
+		outerErr error
+		wantLog  []string
+	}{
+		{
+			name: "after-commit handlers of the inner transaction wait for the outermost commit",
+			wantLog: []string{
+				"BEGIN",
+				"SAVEPOINT", "before A", "RELEASE SAVEPOINT",
+				"before B",
+				"COMMIT",
+				"after A", "after B",
+			},
+		},
+		{
+			name:     "outer rollback drops after-commit handlers of the inner transaction",
+			outerErr: errOuter,
+			wantLog: []string{
+				"BEGIN",
+				"SAVEPOINT", "before A", "RELEASE SAVEPOINT",
+				"ROLLBACK",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &fakeTransactor{}
+			d := domain.NewSimpleDomainEventDispatcher()
+			for _, name := range []domain.EventName{"A", "B"} {
+				d.Register(name, raising(tr, "before"), domain.InTransaction())
+				d.Register(name, raising(tr, "after"))
+			}
+
+			inner := transactionWith(tr, d, handlerRaising("A"))
+			outer := transactionWith(tr, d, func(ctx context.Context, request any) (any, error) {
+				if _, err := inner(ctx, request); err != nil {
+					return nil, err
+				}
+				if tt.outerErr != nil {
+					return nil, tt.outerErr
+				}
+				// event ของ inner ถูก dispatch ไปแล้ว outer dispatch เฉพาะ aggregate ที่ตัวเอง track
+				return handlerRaising("B")(ctx, request)
+			})
+
+			_, err := outer(context.Background(), "request")
+			if !errors.Is(err, tt.outerErr) {
+				t.Fatalf("error = %v, want %v", err, tt.outerErr)
+			}
+			assertLog(t, tr, tt.wantLog...)
+		})
+	}
+}
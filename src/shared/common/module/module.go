@@ -1,7 +1,13 @@
 package module
 
 import (
+	"go-mma/shared/common/cache"
+	"go-mma/shared/common/domain"
 	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/eventstore"
+	"go-mma/shared/common/inbox"
+	"go-mma/shared/common/mediator"
+	"go-mma/shared/common/outbox"
 	"go-mma/shared/common/registry"
 	"go-mma/shared/common/storage/sqldb/transactor"
 
@@ -22,11 +28,21 @@ type ServiceProvider interface {
 type ModuleContext struct {
 	Transactor transactor.Transactor
 	DBCtx      transactor.DBContext
+	Codec      eventbus.Codec
+	Outbox     outbox.Outbox
+	Inbox      inbox.Inbox
+	EventStore domain.EventStore  // ใช้กับ aggregate แบบ event-sourced
+	Mediator   *mediator.Mediator // ถูกสร้างโดย Application ก่อนเรียก Init ของโมดูล
+	QueryCache cache.Cache        // cache ของ mediator.Caching ใช้ invalidate เมื่อข้อมูลเปลี่ยน
 }
 
 func NewModuleContext(transactor transactor.Transactor, dbCtx transactor.DBContext) *ModuleContext {
 	return &ModuleContext{
 		Transactor: transactor,
 		DBCtx:      dbCtx,
+		Codec:      eventbus.DefaultCodec,
+		Outbox:     outbox.NewOutbox(dbCtx, eventbus.DefaultCodec),
+		Inbox:      inbox.NewInbox(transactor, dbCtx),
+		EventStore: eventstore.NewStore(dbCtx),
 	}
 }
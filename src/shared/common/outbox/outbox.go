@@ -0,0 +1,61 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/storage/sqldb/transactor"
+	"time"
+)
+
+var (
+	ErrNoTransaction = errors.New("outbox: events must be added within a transaction")
+)
+
+// Outbox บันทึก integration event ลงตาราง outbox ภายใน transaction เดียวกับข้อมูลของโมดูล
+type Outbox interface {
+	Add(ctx context.Context, events ...eventbus.Event) error
+}
+
+type sqlOutbox struct {
+	dbCtx transactor.DBContext
+	codec eventbus.Codec
+}
+
+func NewOutbox(dbCtx transactor.DBContext, codec eventbus.Codec) Outbox {
+	return &sqlOutbox{
+		dbCtx: dbCtx,
+		codec: codec,
+	}
+}
+
+// Add ต้องถูกเรียกภายใน transactor.WithinTransaction เสมอ
+// เพื่อให้ event ถูก commit พร้อมกับข้อมูล หรือ rollback ไปด้วยกัน
+func (o *sqlOutbox) Add(ctx context.Context, events ...eventbus.Event) error {
+	if !transactor.IsWithinTransaction(ctx) {
+		return ErrNoTransaction
+	}
+
+	query := `
+	INSERT INTO public.outbox (event_id, event_name, payload, occurred_at)
+	VALUES ($1, $2, $3, $4)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	for _, event := range events {
+		payload, err := o.codec.Encode(event, eventbus.MetadataFromContext(ctx))
+		if err != nil {
+			return fmt.Errorf("outbox: %w", err)
+		}
+
+		_, err = o.dbCtx(ctx).ExecContext(ctx, query, event.EventID(), event.EventName(), payload, event.OccurredAt())
+		if err != nil {
+			return errs.HandleDBError(fmt.Errorf("failed to insert outbox event: %w", err))
+		}
+	}
+	return nil
+}
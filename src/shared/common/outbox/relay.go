@@ -0,0 +1,200 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/logger"
+	"go-mma/shared/common/storage/sqldb/transactor"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type message struct {
+	ID        int64  `db:"id"`
+	EventID   string `db:"event_id"`
+	EventName string `db:"event_name"`
+	Payload   []byte `db:"payload"`
+}
+
+// Relay อ่าน event ที่ยังไม่ถูก publish จากตาราง outbox แล้วส่งต่อให้ EventBus
+type Relay struct {
+	transactor  transactor.Transactor
+	dbCtx       transactor.DBContext
+	codec       eventbus.Codec
+	eventBus    eventbus.EventBus
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type RelayOption func(*Relay)
+
+func NewRelay(t transactor.Transactor, dbCtx transactor.DBContext, codec eventbus.Codec, eventBus eventbus.EventBus, opts ...RelayOption) *Relay {
+	r := &Relay{
+		transactor:  t,
+		dbCtx:       dbCtx,
+		codec:       codec,
+		eventBus:    eventBus,
+		interval:    time.Second,
+		batchSize:   100,
+		maxAttempts: 10,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func WithPollInterval(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		if d > 0 {
+			r.interval = d
+		}
+	}
+}
+
+func WithBatchSize(n int) RelayOption {
+	return func(r *Relay) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+// WithMaxAttempts กำหนดจำนวนครั้งสูงสุดที่จะลองส่ง event ก่อนจะข้ามไป
+func WithMaxAttempts(n int) RelayOption {
+	return func(r *Relay) {
+		if n > 0 {
+			r.maxAttempts = n
+		}
+	}
+}
+
+// Start เริ่ม poll ตาราง outbox ใน background จนกว่าจะเรียก Stop
+func (r *Relay) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.relayBatch(ctx); err != nil && ctx.Err() == nil {
+					logger.Log.Error(fmt.Sprintf("outbox relay error: %v", err))
+				}
+			}
+		}
+	}()
+}
+
+// Stop หยุด relay และรอให้ batch ปัจจุบันทำงานเสร็จ แต่ไม่เกินเวลาของ ctx
+func (r *Relay) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Relay) relayBatch(ctx context.Context) error {
+	return r.transactor.WithinTransaction(ctx, func(ctx context.Context, _ func(transactor.PostCommitHook)) error {
+		// SKIP LOCKED ทำให้รันหลาย instance พร้อมกันได้โดยไม่ส่ง event ซ้ำ
+		query := `
+		SELECT id, event_id, event_name, payload
+		FROM public.outbox
+		WHERE published_at IS NULL
+		AND attempts < $1
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+		`
+
+		var messages []message
+		if err := r.dbCtx(ctx).SelectContext(ctx, &messages, query, r.maxAttempts, r.batchSize); err != nil {
+			return errs.HandleDBError(fmt.Errorf("failed to select outbox events: %w", err))
+		}
+
+		for _, m := range messages {
+			if err := r.publish(ctx, m); err != nil {
+				logger.Log.Error("failed to relay outbox event",
+					zap.String("eventId", m.EventID),
+					zap.String("eventName", m.EventName),
+					zap.Error(err),
+				)
+				if err := r.markFailed(ctx, m.ID, err); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := r.markPublished(ctx, m.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Relay) publish(ctx context.Context, m message) error {
+	event, env, err := r.codec.Decode(m.Payload)
+	if err != nil {
+		return err
+	}
+	// handler ปลายทางต้องไม่ใช้ transaction ของ relay
+	ctx = eventbus.ContextWithMetadata(transactor.WithoutTransaction(ctx), env.Metadata)
+	return r.eventBus.Publish(ctx, event)
+}
+
+func (r *Relay) markPublished(ctx context.Context, id int64) error {
+	query := `
+	UPDATE public.outbox
+	SET published_at = current_timestamp,
+		attempts = attempts + 1
+	WHERE id = $1
+	`
+	if _, err := r.dbCtx(ctx).ExecContext(ctx, query, id); err != nil {
+		return errs.HandleDBError(fmt.Errorf("failed to mark outbox event as published: %w", err))
+	}
+	return nil
+}
+
+func (r *Relay) markFailed(ctx context.Context, id int64, cause error) error {
+	query := `
+	UPDATE public.outbox
+	SET attempts = attempts + 1,
+		last_error = $2
+	WHERE id = $1
+	`
+	if _, err := r.dbCtx(ctx).ExecContext(ctx, query, id, cause.Error()); err != nil {
+		return errs.HandleDBError(fmt.Errorf("failed to update outbox event: %w", err))
+	}
+	return nil
+}
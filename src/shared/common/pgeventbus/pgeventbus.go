@@ -0,0 +1,312 @@
+package pgeventbus
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-mma/shared/common/errs"
+	"go-mma/shared/common/eventbus"
+	"go-mma/shared/common/logger"
+	"go-mma/shared/common/storage/sqldb/transactor"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const (
+	channel = "event_bus"
+	// catchUpInterval คือรอบที่อ่านตารางเพื่อหา event ที่ไม่ได้รับ NOTIFY และ ping connection ของ listener
+	catchUpInterval = 90 * time.Second
+	pruneInterval   = time.Hour
+)
+
+type eventRow struct {
+	ID        int64  `db:"id"`
+	TxID      int64  `db:"tx_id"`
+	EventName string `db:"event_name"`
+	Payload   []byte `db:"payload"`
+}
+
+// position คือตำแหน่งของแถวใน event_bus_events เรียงตาม transaction ที่ insert แล้วตาม id
+// id อย่างเดียวใช้เป็น cursor ไม่ได้ เพราะแถวที่ id น้อยกว่าอาจ commit ทีหลังแถวที่ id มากกว่า
+type position struct {
+	txID int64
+	id   int64
+}
+
+func (p position) before(other position) bool {
+	return p.txID < other.txID || (p.txID == other.txID && p.id < other.id)
+}
+
+// PostgresEventBus กระจาย integration event ข้ามทุก instance ผ่าน LISTEN/NOTIFY
+// event จะถูกบันทึกลงตาราง event_bus_events ก่อน แล้ว NOTIFY เฉพาะ id ของแถว
+// เพราะ payload ของ NOTIFY จำกัดขนาดไว้ที่ 8000 bytes
+type PostgresEventBus struct {
+	dsn   string
+	dbCtx transactor.DBContext
+	codec eventbus.Codec
+	local *eventbus.InMemoryEventBus
+
+	retention time.Duration
+
+	listener *pq.Listener
+	// cursor คือตำแหน่งที่ catch up อ่านครบแล้ว แถวก่อน cursor จะไม่มีแถวใหม่เพิ่มเข้ามาอีก
+	cursor position
+	// seen คือแถวหลัง cursor ที่ dispatch ไปแล้วจาก NOTIFY ใช้กันส่งซ้ำตอน catch up
+	seen      map[int64]position
+	lastPrune time.Time
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+type Option func(*PostgresEventBus)
+
+// WithRetention ลบ event ที่เก่ากว่า d ออกจาก event_bus_events (ค่า 0 คือไม่ลบ)
+// ควรนานกว่าเวลาที่ instance อาจหลุดจากฐานข้อมูลได้ เพราะ event ที่ถูกลบไปแล้วจะ catch up ไม่ได้
+func WithRetention(d time.Duration) Option {
+	return func(b *PostgresEventBus) {
+		b.retention = d
+	}
+}
+
+var _ eventbus.EventBus = (*PostgresEventBus)(nil)
+
+// New สร้าง event bus โดย dsn ใช้เปิด connection แยกสำหรับ LISTEN
+// ส่วน dbCtx ใช้บันทึก event (ถ้า Publish ภายใน transaction, NOTIFY จะถูกส่งหลัง commit)
+// busOpts ใช้กับ event bus ภายในที่ส่ง event ให้ subscriber ของ instance นี้
+func New(dsn string, dbCtx transactor.DBContext, codec eventbus.Codec, busOpts []eventbus.Option, opts ...Option) *PostgresEventBus {
+	b := &PostgresEventBus{
+		dsn:   dsn,
+		dbCtx: dbCtx,
+		codec: codec,
+		local: eventbus.NewInMemoryEventBus(append([]eventbus.Option{eventbus.WithCodec(codec)}, busOpts...)...),
+		seen:  make(map[int64]position),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+func (b *PostgresEventBus) Subscribe(eventName eventbus.EventName, handler eventbus.IntegrationEventHandler, opts ...eventbus.SubscribeOption) eventbus.Subscription {
+	return b.local.Subscribe(eventName, handler, opts...)
+}
+
+func (b *PostgresEventBus) Subscriptions() map[eventbus.EventName][]string {
+	return b.local.Subscriptions()
+}
+
+func (b *PostgresEventBus) Deliver(ctx context.Context, handlerName string, event eventbus.Event) error {
+	return b.local.Deliver(ctx, handlerName, event)
+}
+
+func (b *PostgresEventBus) Publish(ctx context.Context, event eventbus.Event) error {
+	if b.local.Closed() {
+		return eventbus.ErrBusClosed
+	}
+
+	payload, err := b.codec.Encode(event, eventbus.MetadataFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	query := `
+	WITH inserted AS (
+		INSERT INTO public.event_bus_events (event_id, event_name, payload, occurred_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	)
+	SELECT pg_notify($5, id::text) FROM inserted
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err = b.dbCtx(ctx).ExecContext(ctx, query, event.EventID(), event.EventName(), payload, event.OccurredAt(), channel)
+	if err != nil {
+		return errs.HandleDBError(fmt.Errorf("failed to publish event: %w", err))
+	}
+	return nil
+}
+
+// Start เปิด connection สำหรับ LISTEN และเริ่มรับ notification ใน background
+// event ที่ commit ก่อน Start จะไม่ถูกส่ง ยกเว้นแถวที่ transaction ยังไม่จบตอนเริ่ม (รวมถึงแถวที่ commit ใกล้ๆ กัน)
+// ซึ่งอาจถูกส่งซ้ำกับ instance ก่อนหน้า subscriber จึงควรใช้ inbox
+func (b *PostgresEventBus) Start() error {
+	if err := b.loadCursor(context.Background()); err != nil {
+		return err
+	}
+
+	b.listener = pq.NewListener(b.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("event bus listener error: %v", err))
+		}
+	})
+	if err := b.listener.Listen(channel); err != nil {
+		_ = b.listener.Close()
+		return fmt.Errorf("failed to listen on channel %s: %w", channel, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.listen(ctx)
+	}()
+
+	return nil
+}
+
+// Drain หยุดรับ notification ปิด connection ของ listener แล้วรอ handler ที่ยังทำงานอยู่ให้เสร็จ
+func (b *PostgresEventBus) Drain(ctx context.Context) (int, error) {
+	if b.cancel != nil {
+		b.cancel()
+		b.wg.Wait()
+		if err := b.listener.Close(); err != nil {
+			logger.Log.Error(fmt.Sprintf("failed to close event bus listener: %v", err))
+		}
+	}
+	return b.local.Drain(ctx)
+}
+
+// listen ทำงานใน goroutine เดียว cursor และ seen จึงไม่ต้องใช้ lock
+func (b *PostgresEventBus) listen(ctx context.Context) {
+	ticker := time.NewTicker(catchUpInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-b.listener.Notify:
+			if n == nil {
+				// connection หลุดแล้วต่อใหม่ notification ระหว่างนั้นจะหายไป จึงต้องดึงจากตารางเอง
+				b.catchUp(ctx)
+				continue
+			}
+			id, err := strconv.ParseInt(n.Extra, 10, 64)
+			if err != nil {
+				logger.Log.Error("invalid event bus notification", zap.String("payload", n.Extra))
+				continue
+			}
+			b.dispatchByID(ctx, id)
+		case <-ticker.C:
+			go func() {
+				_ = b.listener.Ping()
+			}()
+			// catch up เป็นระยะด้วย เพื่อเลื่อน cursor และล้าง seen แม้ connection ไม่เคยหลุด
+			b.catchUp(ctx)
+			b.prune(ctx)
+		}
+	}
+}
+
+func (b *PostgresEventBus) dispatchByID(ctx context.Context, id int64) {
+	query := `
+	SELECT id, tx_id::text::bigint AS tx_id, event_name, payload
+	FROM public.event_bus_events
+	WHERE id = $1
+	`
+
+	var row eventRow
+	if err := b.dbCtx(ctx).QueryRowxContext(ctx, query, id).StructScan(&row); err != nil {
+		if err != sql.ErrNoRows {
+			logger.Log.Error(fmt.Sprintf("failed to load event %d: %v", id, err))
+		}
+		return
+	}
+
+	pos := position{txID: row.TxID, id: row.ID}
+	if !b.cursor.before(pos) {
+		return // catch up ส่งไปแล้ว
+	}
+	if _, ok := b.seen[row.ID]; ok {
+		return
+	}
+	b.seen[row.ID] = pos
+	b.dispatch(ctx, row)
+}
+
+// catchUp ส่ง event หลัง cursor ที่ยังไม่ได้ส่ง โดยอ่านเฉพาะแถวของ transaction ที่เก่ากว่า xmin ของ snapshot ปัจจุบัน
+// transaction เหล่านั้นจบไปหมดแล้ว จึงไม่มีแถวใหม่ที่มาก่อน cursor เพิ่มขึ้นอีก
+func (b *PostgresEventBus) catchUp(ctx context.Context) {
+	query := `
+	SELECT id, tx_id::text::bigint AS tx_id, event_name, payload
+	FROM public.event_bus_events
+	WHERE (tx_id, id) > ($1::text::xid8, $2)
+	AND tx_id < pg_snapshot_xmin(pg_current_snapshot())
+	ORDER BY tx_id, id
+	`
+
+	var rows []eventRow
+	if err := b.dbCtx(ctx).SelectContext(ctx, &rows, query, b.cursor.txID, b.cursor.id); err != nil {
+		logger.Log.Error(fmt.Sprintf("failed to load missed events: %v", err))
+		return
+	}
+	for _, row := range rows {
+		b.cursor = position{txID: row.TxID, id: row.ID}
+		if _, ok := b.seen[row.ID]; ok {
+			continue
+		}
+		b.dispatch(ctx, row)
+	}
+
+	for id, pos := range b.seen {
+		if !b.cursor.before(pos) {
+			delete(b.seen, id)
+		}
+	}
+}
+
+// prune ลบ event ที่เก่ากว่า retention ทุก instance ทำได้พร้อมกันเพราะลบซ้ำก็ไม่มีผล
+func (b *PostgresEventBus) prune(ctx context.Context) {
+	if b.retention <= 0 || time.Since(b.lastPrune) < pruneInterval {
+		return
+	}
+	b.lastPrune = time.Now()
+
+	query := `
+	DELETE FROM public.event_bus_events
+	WHERE created_at < current_timestamp - make_interval(secs => $1)
+	`
+
+	res, err := b.dbCtx(ctx).ExecContext(ctx, query, b.retention.Seconds())
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("failed to prune event bus events: %v", err))
+		return
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		logger.Log.Info(fmt.Sprintf("pruned %d event bus event(s)", n))
+	}
+}
+
+func (b *PostgresEventBus) dispatch(ctx context.Context, row eventRow) {
+	event, env, err := b.codec.Decode(row.Payload)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("failed to decode event %d: %v", row.ID, err))
+		return
+	}
+
+	// ใช้ context ใหม่ เพราะ ctx ของ listener จะถูก cancel ตอน shutdown ก่อน handler ทำงานเสร็จ
+	ctx = eventbus.ContextWithMetadata(context.WithoutCancel(ctx), env.Metadata)
+	if err := b.local.Publish(ctx, event); err != nil {
+		logger.Log.Error(fmt.Sprintf("failed to dispatch event %d: %v", row.ID, err))
+	}
+}
+
+// loadCursor เริ่ม cursor ที่ xmin ปัจจุบัน แถวของ transaction ที่ยังไม่จบจะถูกส่งตอน catch up
+func (b *PostgresEventBus) loadCursor(ctx context.Context) error {
+	query := `SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint`
+
+	var xmin int64
+	if err := b.dbCtx(ctx).GetContext(ctx, &xmin, query); err != nil {
+		return errs.HandleDBError(fmt.Errorf("failed to load event bus cursor: %w", err))
+	}
+	b.cursor = position{txID: xmin, id: 0}
+	return nil
+}
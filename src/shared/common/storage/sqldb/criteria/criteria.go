@@ -0,0 +1,326 @@
+package criteria
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidIdentifier = errors.New("invalid SQL identifier")
+	ErrInvalidPage       = errors.New("limit and offset must not be negative")
+)
+
+// identifier รับเฉพาะชื่อคอลัมน์หรือตาราง (มี schema นำหน้าได้) เพราะชื่อเหล่านี้ถูกต่อเข้า SQL ตรงๆ
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+func checkIdentifier(name string) error {
+	if !identifier.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidIdentifier, name)
+	}
+	return nil
+}
+
+// Predicate คือเงื่อนไขใน WHERE ค่าทุกตัวถูกส่งเป็น parameter ($1, $2, ...) ไม่ถูกต่อเข้า SQL
+// สร้างได้จากฟังก์ชันในแพ็กเกจนี้เท่านั้น เช่น Eq, Gt, In, And, Or, Not
+type Predicate interface {
+	build(b *builder) error
+}
+
+type comparison struct {
+	column string
+	op     string
+	value  any
+	fold   bool
+}
+
+func (p comparison) build(b *builder) error {
+	if err := checkIdentifier(p.column); err != nil {
+		return err
+	}
+	if p.fold {
+		b.write("lower(" + p.column + ") " + p.op + " lower(" + b.arg(p.value) + ")")
+		return nil
+	}
+	b.write(p.column + " " + p.op + " " + b.arg(p.value))
+	return nil
+}
+
+func Eq(column string, value any) Predicate {
+	return comparison{column: column, op: "=", value: value}
+}
+
+func Ne(column string, value any) Predicate {
+	return comparison{column: column, op: "<>", value: value}
+}
+
+func Gt(column string, value any) Predicate {
+	return comparison{column: column, op: ">", value: value}
+}
+
+func Gte(column string, value any) Predicate {
+	return comparison{column: column, op: ">=", value: value}
+}
+
+func Lt(column string, value any) Predicate {
+	return comparison{column: column, op: "<", value: value}
+}
+
+func Lte(column string, value any) Predicate {
+	return comparison{column: column, op: "<=", value: value}
+}
+
+// EqFold เทียบแบบไม่สนตัวพิมพ์เล็กใหญ่
+func EqFold(column string, value any) Predicate {
+	return comparison{column: column, op: "=", value: value, fold: true}
+}
+
+// Like ใช้ pattern ของ SQL (% และ _) ผู้เรียกต้อง escape อักขระเหล่านี้เองถ้าเป็นข้อมูลจากผู้ใช้
+func Like(column string, pattern string) Predicate {
+	return comparison{column: column, op: "LIKE", value: pattern}
+}
+
+type nullCheck struct {
+	column string
+	null   bool
+}
+
+func (p nullCheck) build(b *builder) error {
+	if err := checkIdentifier(p.column); err != nil {
+		return err
+	}
+	if p.null {
+		b.write(p.column + " IS NULL")
+	} else {
+		b.write(p.column + " IS NOT NULL")
+	}
+	return nil
+}
+
+func IsNull(column string) Predicate {
+	return nullCheck{column: column, null: true}
+}
+
+func IsNotNull(column string) Predicate {
+	return nullCheck{column: column, null: false}
+}
+
+type in struct {
+	column string
+	values []any
+}
+
+// In ที่ไม่มีค่าเลยจะเป็นเงื่อนไขที่ไม่มีแถวใดตรง
+func In[T any](column string, values ...T) Predicate {
+	p := in{column: column, values: make([]any, 0, len(values))}
+	for _, v := range values {
+		p.values = append(p.values, v)
+	}
+	return p
+}
+
+func (p in) build(b *builder) error {
+	if err := checkIdentifier(p.column); err != nil {
+		return err
+	}
+	if len(p.values) == 0 {
+		b.write("FALSE")
+		return nil
+	}
+	placeholders := make([]string, 0, len(p.values))
+	for _, v := range p.values {
+		placeholders = append(placeholders, b.arg(v))
+	}
+	b.write(p.column + " IN (" + strings.Join(placeholders, ", ") + ")")
+	return nil
+}
+
+type junction struct {
+	op    string
+	preds []Predicate
+}
+
+// And ที่ไม่มีเงื่อนไขเลยจะเป็นจริงเสมอ
+func And(preds ...Predicate) Predicate {
+	return junction{op: "AND", preds: preds}
+}
+
+// Or ที่ไม่มีเงื่อนไขเลยจะเป็นเท็จเสมอ
+func Or(preds ...Predicate) Predicate {
+	return junction{op: "OR", preds: preds}
+}
+
+func (p junction) build(b *builder) error {
+	preds := make([]Predicate, 0, len(p.preds))
+	for _, pred := range p.preds {
+		if pred != nil {
+			preds = append(preds, pred)
+		}
+	}
+
+	switch len(preds) {
+	case 0:
+		if p.op == "AND" {
+			b.write("TRUE")
+		} else {
+			b.write("FALSE")
+		}
+		return nil
+	case 1:
+		return preds[0].build(b)
+	}
+
+	b.write("(")
+	for i, pred := range preds {
+		if i > 0 {
+			b.write(" " + p.op + " ")
+		}
+		if err := pred.build(b); err != nil {
+			return err
+		}
+	}
+	b.write(")")
+	return nil
+}
+
+type not struct {
+	pred Predicate
+}
+
+// Not ของ nil ไม่เพิ่มเงื่อนไข (เป็นจริงเสมอ) เหมือนที่ And และ Or ข้าม predicate ที่เป็น nil
+func Not(pred Predicate) Predicate {
+	return not{pred: pred}
+}
+
+func (p not) build(b *builder) error {
+	if p.pred == nil {
+		b.write("TRUE")
+		return nil
+	}
+	b.write("NOT (")
+	if err := p.pred.build(b); err != nil {
+		return err
+	}
+	b.write(")")
+	return nil
+}
+
+type Direction string
+
+const (
+	Asc  Direction = "ASC"
+	Desc Direction = "DESC"
+)
+
+type order struct {
+	column    string
+	direction Direction
+}
+
+// Criteria รวมเงื่อนไข การเรียงลำดับ และการแบ่งหน้า แล้วแปลงเป็น SQL แบบ parameterized สำหรับ sqlx
+// ค่าเริ่มต้นคือทุกแถว ไม่เรียง ไม่จำกัดจำนวน
+type Criteria struct {
+	where  []Predicate
+	orders []order
+	limit  int
+	offset int
+}
+
+// Where สร้าง Criteria ที่ทุก predicate ต้องเป็นจริง
+func Where(preds ...Predicate) *Criteria {
+	return &Criteria{where: preds}
+}
+
+// And เพิ่มเงื่อนไขที่ต้องเป็นจริงด้วย
+func (c *Criteria) And(preds ...Predicate) *Criteria {
+	c.where = append(c.where, preds...)
+	return c
+}
+
+// OrderBy เรียงตามคอลัมน์ เรียกหลายครั้งได้ ลำดับการเรียกคือลำดับความสำคัญ
+func (c *Criteria) OrderBy(column string, direction Direction) *Criteria {
+	c.orders = append(c.orders, order{column: column, direction: direction})
+	return c
+}
+
+// Limit ค่า 0 คือไม่จำกัด
+func (c *Criteria) Limit(n int) *Criteria {
+	c.limit = n
+	return c
+}
+
+func (c *Criteria) Offset(n int) *Criteria {
+	c.offset = n
+	return c
+}
+
+// Page แบ่งหน้าโดย page เริ่มที่ 1 ควรใช้คู่กับ OrderBy เพื่อให้ผลลัพธ์แต่ละหน้าคงที่
+func (c *Criteria) Page(page int, size int) *Criteria {
+	if page < 1 {
+		page = 1
+	}
+	c.limit = size
+	c.offset = (page - 1) * size
+	return c
+}
+
+// Build ต่อ WHERE, ORDER BY, LIMIT และ OFFSET ท้าย base เช่น "SELECT * FROM public.orders"
+// คืน query และ args สำหรับส่งให้ SelectContext, GetContext หรือ QueryxContext ของ transactor.DBContext
+func (c *Criteria) Build(base string) (string, []any, error) {
+	b := &builder{}
+	b.write(strings.TrimRight(base, " \t\n"))
+
+	if c == nil {
+		return b.sql.String(), b.args, nil
+	}
+
+	if len(c.where) > 0 {
+		b.write("\nWHERE ")
+		if err := And(c.where...).build(b); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if len(c.orders) > 0 {
+		b.write("\nORDER BY ")
+		for i, o := range c.orders {
+			if err := checkIdentifier(o.column); err != nil {
+				return "", nil, err
+			}
+			if o.direction != Asc && o.direction != Desc {
+				return "", nil, fmt.Errorf("invalid sort direction: %q", o.direction)
+			}
+			if i > 0 {
+				b.write(", ")
+			}
+			b.write(o.column + " " + string(o.direction))
+		}
+	}
+
+	if c.limit < 0 || c.offset < 0 {
+		return "", nil, ErrInvalidPage
+	}
+	if c.limit > 0 {
+		b.write("\nLIMIT " + b.arg(c.limit))
+	}
+	if c.offset > 0 {
+		b.write("\nOFFSET " + b.arg(c.offset))
+	}
+	return b.sql.String(), b.args, nil
+}
+
+type builder struct {
+	sql  strings.Builder
+	args []any
+}
+
+func (b *builder) write(s string) {
+	b.sql.WriteString(s)
+}
+
+// arg เก็บค่าไว้ใน args แล้วคืน placeholder ของ postgres
+func (b *builder) arg(v any) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
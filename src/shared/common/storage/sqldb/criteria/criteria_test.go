@@ -0,0 +1,127 @@
+package criteria
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const base = "SELECT * FROM public.orders"
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria *Criteria
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "nil criteria",
+			wantSQL: base,
+		},
+		{
+			name:     "placeholders are numbered across And, Or and In",
+			criteria: Where(Eq("customer_id", 7), Or(In("status", "paid", "shipped"), Gt("total", 100))).And(Like("note", "%gift%")),
+			wantSQL:  base + "\nWHERE (customer_id = $1 AND (status IN ($2, $3) OR total > $4) AND note LIKE $5)",
+			wantArgs: []any{7, "paid", "shipped", 100, "%gift%"},
+		},
+		{
+			name:     "paging placeholders follow where",
+			criteria: Where(EqFold("email", "A@B.C")).OrderBy("created_at", Desc).OrderBy("id", Asc).Page(3, 20),
+			wantSQL:  base + "\nWHERE lower(email) = lower($1)\nORDER BY created_at DESC, id ASC\nLIMIT $2\nOFFSET $3",
+			wantArgs: []any{"A@B.C", 20, 40},
+		},
+		{
+			name:     "empty In and junctions",
+			criteria: Where(In[int]("id"), Or(), And()),
+			wantSQL:  base + "\nWHERE (FALSE AND FALSE AND TRUE)",
+		},
+		{
+			name:     "nil predicates are skipped",
+			criteria: Where(nil, Not(nil), Or(nil, IsNull("canceled_at")), Not(Ne("status", "draft"))),
+			wantSQL:  base + "\nWHERE (TRUE AND canceled_at IS NULL AND NOT (status <> $1))",
+			wantArgs: []any{"draft"},
+		},
+		{
+			name:     "zero limit and offset are omitted",
+			criteria: Where(IsNotNull("paid_at")).Limit(0).Offset(0),
+			wantSQL:  base + "\nWHERE paid_at IS NOT NULL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.criteria.Build(base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != tt.wantSQL {
+				t.Errorf("query = %q, want %q", query, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBuildRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria *Criteria
+		wantErr  error
+	}{
+		{name: "column with injection", criteria: Where(Eq("id; DROP TABLE orders", 1)), wantErr: ErrInvalidIdentifier},
+		{name: "quoted column", criteria: Where(IsNull(`"id"`)), wantErr: ErrInvalidIdentifier},
+		{name: "column inside In", criteria: Where(In("id)", 1)), wantErr: ErrInvalidIdentifier},
+		{name: "column inside Or", criteria: Where(Or(Eq("id", 1), Gt("total > 0 OR 1", 1))), wantErr: ErrInvalidIdentifier},
+		{name: "column inside Not", criteria: Where(Not(Eq("", 1))), wantErr: ErrInvalidIdentifier},
+		{name: "sort column", criteria: Where().OrderBy("id DESC, (SELECT 1)", Asc), wantErr: ErrInvalidIdentifier},
+		{name: "negative limit", criteria: Where().Limit(-1), wantErr: ErrInvalidPage},
+		{name: "negative offset", criteria: Where().Offset(-10), wantErr: ErrInvalidPage},
+		{name: "negative page size", criteria: Where().Page(2, -5), wantErr: ErrInvalidPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.criteria.Build(base)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if query != "" || args != nil {
+				t.Fatalf("query = %q, args = %v, want nothing on error", query, args)
+			}
+		})
+	}
+}
+
+func TestBuildRejectsInvalidSortDirection(t *testing.T) {
+	if _, _, err := Where().OrderBy("id", "ASC; DROP TABLE orders").Build(base); err == nil {
+		t.Fatal("error = nil, want invalid sort direction")
+	}
+}
+
+// table ถูกตรวจก่อนใช้ฐานข้อมูล จึงส่ง DBContext เป็น nil ได้
+func TestQueriesRejectInvalidTable(t *testing.T) {
+	ctx := context.Background()
+	table := "orders; DROP TABLE orders"
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{name: "Select", run: func() error { _, err := Select[struct{}](ctx, nil, table, nil); return err }},
+		{name: "First", run: func() error { _, err := First[struct{}](ctx, nil, table, nil); return err }},
+		{name: "Exists", run: func() error { _, err := Exists(ctx, nil, table, nil); return err }},
+		{name: "Count", run: func() error { _, err := Count(ctx, nil, table, nil); return err }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, ErrInvalidIdentifier) {
+				t.Fatalf("error = %v, want %v", err, ErrInvalidIdentifier)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package criteria
+
+import (
+	"context"
+	"database/sql"
+	"go-mma/shared/common/storage/sqldb/transactor"
+)
+
+// ฟังก์ชันในไฟล์นี้ใช้ dbCtx(ctx) จึงทำงานภายใน transaction ของ ctx ถ้ามี
+// error ที่คืนเป็น error ดิบของฐานข้อมูล ให้ repository ห่อด้วย errs.HandleDBError เหมือน query อื่น
+
+// Select อ่านทุกแถวของ table ที่ตรงกับ c แล้ว map เข้า T ด้วย tag `db`
+func Select[T any](ctx context.Context, dbCtx transactor.DBContext, table string, c *Criteria) ([]*T, error) {
+	if err := checkIdentifier(table); err != nil {
+		return nil, err
+	}
+	query, args, err := c.Build("SELECT * FROM " + table)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*T
+	if err := dbCtx(ctx).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// First คืนแถวแรกที่ตรงกับ c หรือ nil ถ้าไม่มี
+func First[T any](ctx context.Context, dbCtx transactor.DBContext, table string, c *Criteria) (*T, error) {
+	if err := checkIdentifier(table); err != nil {
+		return nil, err
+	}
+	first := c.clone().Limit(1)
+	query, args, err := first.Build("SELECT * FROM " + table)
+	if err != nil {
+		return nil, err
+	}
+
+	var row T
+	if err := dbCtx(ctx).QueryRowxContext(ctx, query, args...).StructScan(&row); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &row, nil
+}
+
+// Exists ตรวจว่ามีแถวที่ตรงกับเงื่อนไขของ c หรือไม่ (ไม่สนการเรียงและการแบ่งหน้า)
+func Exists(ctx context.Context, dbCtx transactor.DBContext, table string, c *Criteria) (bool, error) {
+	if err := checkIdentifier(table); err != nil {
+		return false, err
+	}
+	query, args, err := c.whereOnly().Build("SELECT 1 FROM " + table)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := dbCtx(ctx).GetContext(ctx, &exists, "SELECT EXISTS ("+query+")", args...); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// Count นับแถวที่ตรงกับเงื่อนไขของ c (ไม่สนการเรียงและการแบ่งหน้า) ใช้คู่กับ Page เพื่อหาจำนวนหน้าทั้งหมด
+func Count(ctx context.Context, dbCtx transactor.DBContext, table string, c *Criteria) (int64, error) {
+	if err := checkIdentifier(table); err != nil {
+		return 0, err
+	}
+	query, args, err := c.whereOnly().Build("SELECT COUNT(*) FROM " + table)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := dbCtx(ctx).GetContext(ctx, &count, query, args...); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (c *Criteria) clone() *Criteria {
+	if c == nil {
+		return &Criteria{}
+	}
+	return &Criteria{
+		where:  append([]Predicate(nil), c.where...),
+		orders: append([]order(nil), c.orders...),
+		limit:  c.limit,
+		offset: c.offset,
+	}
+}
+
+func (c *Criteria) whereOnly() *Criteria {
+	if c == nil {
+		return nil
+	}
+	return &Criteria{where: c.where}
+}
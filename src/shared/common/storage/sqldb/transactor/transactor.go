@@ -3,16 +3,28 @@ package transactor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"go-mma/shared/common/inflight"
 	"go-mma/shared/common/logger"
 
 	"github.com/jmoiron/sqlx"
 )
 
+var (
+	ErrNoTransaction = errors.New("not within a transaction")
+)
+
 type PostCommitHook func(ctx context.Context) error
 
 type Transactor interface {
+	// WithinTransaction รัน txFunc ใน transaction ถ้า ctx อยู่ใน transaction แล้วจะเป็น transaction ซ้อน
+	// post-commit hook ของ transaction ซ้อนจะรันเมื่อ transaction ชั้นนอกสุด commit เท่านั้น
 	WithinTransaction(ctx context.Context, txFunc func(ctxWithTx context.Context, registerPostCommitHook func(PostCommitHook)) error) error
+	// Drain หยุดรัน post-commit hook ของ transaction ที่ commit หลังจากนี้ และรอ hook ที่ยังทำงานอยู่ให้เสร็จ
+	// transaction ใหม่ยังทำงานได้ตามปกติ เพราะงานที่ยังค้างอยู่ เช่น handler ของ event bus อาจต้องใช้
+	// คืนจำนวน hook ที่ยังไม่เสร็จเมื่อ ctx หมดเวลา
+	Drain(ctx context.Context) (int, error)
 }
 
 type (
@@ -23,6 +35,7 @@ type (
 type sqlTransactor struct {
 	sqlxDBGetter
 	nestedTransactionsStrategy
+	hooks *inflight.Tracker
 }
 
 type Option func(*sqlTransactor)
@@ -36,6 +49,7 @@ func New(db *sqlx.DB, opts ...Option) (Transactor, DBContext) {
 			return db
 		},
 		nestedTransactionsStrategy: NestedTransactionsNone, // Default strategy
+		hooks:                      inflight.NewTracker(),
 	}
 
 	for _, opt := range opts {
@@ -78,6 +92,7 @@ func (t *sqlTransactor) WithinTransaction(ctx context.Context, txFunc func(ctxWi
 		_ = currentTX.Rollback() // If rollback fails, there's nothing to do, the transaction will expire by itself
 	}()
 	ctxWithTx := txToContext(ctx, newDB)
+	ctxWithTx = context.WithValue(ctxWithTx, hookRegistrarKey{}, registerPostCommitHook)
 
 	if err := txFunc(ctxWithTx, registerPostCommitHook); err != nil {
 		return err
@@ -87,8 +102,21 @@ func (t *sqlTransactor) WithinTransaction(ctx context.Context, txFunc func(ctxWi
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	// transaction ซ้อน (savepoint) ยังไม่ถูก commit จริง จึงส่ง hook ต่อให้ transaction ชั้นนอก
+	// hook จะรันเมื่อ transaction ชั้นนอกสุด commit และถูกทิ้งถ้าชั้นนอก rollback
+	if parentRegister, ok := hookRegistrar(ctx); ok {
+		for _, hook := range hooks {
+			parentRegister(hook)
+		}
+		return nil
+	}
+
+	if len(hooks) == 0 {
+		return nil
+	}
+
 	// หลังจาก commit แล้ว รัน hook แบบ isolated
-	go func() {
+	err = t.hooks.Go(func() {
 		for _, hook := range hooks {
 			func(h PostCommitHook) {
 				defer func() {
@@ -102,11 +130,47 @@ func (t *sqlTransactor) WithinTransaction(ctx context.Context, txFunc func(ctxWi
 				}
 			}(hook)
 		}
-	}()
+	})
+	if err != nil {
+		// transaction commit ไปแล้ว แต่ระบบกำลังปิด hook จึงไม่ถูกรัน
+		logger.Log.Error(fmt.Sprintf("post-commit hooks skipped: %v", err))
+	}
 
 	return nil
 }
 
+func (t *sqlTransactor) Drain(ctx context.Context) (int, error) {
+	return t.hooks.Drain(ctx)
+}
+
+type hookRegistrarKey struct{}
+
+// hookRegistrar คืนตัวลงทะเบียน hook ของ transaction ที่ ctx อยู่ ถ้า ctx อยู่ใน transaction
+func hookRegistrar(ctx context.Context) (func(PostCommitHook), bool) {
+	register, ok := ctx.Value(hookRegistrarKey{}).(func(PostCommitHook))
+	if !ok || !IsWithinTransaction(ctx) {
+		return nil, false
+	}
+	return register, true
+}
+
+// RegisterPostCommitHook ลงทะเบียน hook ให้ transaction ที่ ctx อยู่ โดยไม่ต้องรับ registerPostCommitHook มาเป็น parameter
+// เหมาะกับ handler ที่ transaction ถูกเปิดให้จากภายนอก เช่น mediator.Transaction
+func RegisterPostCommitHook(ctx context.Context, hook PostCommitHook) error {
+	register, ok := hookRegistrar(ctx)
+	if !ok {
+		return ErrNoTransaction
+	}
+	register(hook)
+	return nil
+}
+
 func IsWithinTransaction(ctx context.Context) bool {
 	return ctx.Value(transactorKey{}) != nil
 }
+
+// WithoutTransaction คืน context ที่ไม่ผูกกับ transaction ปัจจุบัน
+// ใช้เมื่อต้องส่ง context ต่อให้งานที่ทำงานหลัง transaction จบไปแล้ว
+func WithoutTransaction(ctx context.Context) context.Context {
+	return context.WithValue(ctx, transactorKey{}, nil)
+}
@@ -0,0 +1,235 @@
+package transactor
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// recordingDriver เป็น driver ปลอมที่จดคำสั่ง transaction ทั้งหมดลง log เพื่อตรวจลำดับกับ hook
+type recordingDriver struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (d *recordingDriver) record(s string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, s)
+}
+
+func (d *recordingDriver) entries() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.log...)
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) {
+	return &recordingConn{d: d}, nil
+}
+
+type recordingConn struct {
+	d *recordingDriver
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	c.d.record("BEGIN")
+	return &recordingTx{d: c.d}, nil
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.d.record(query)
+	return driver.RowsAffected(0), nil
+}
+
+type recordingTx struct {
+	d *recordingDriver
+}
+
+func (t *recordingTx) Commit() error {
+	t.d.record("COMMIT")
+	return nil
+}
+
+func (t *recordingTx) Rollback() error {
+	t.d.record("ROLLBACK")
+	return nil
+}
+
+var (
+	registerDriver sync.Once
+	testDriver     = &recordingDriver{}
+	testDriverMu   sync.Mutex // test ที่ใช้ driver ตัวเดียวกันต้องไม่ทำงานพร้อมกัน
+)
+
+func newTestTransactor(t *testing.T) (Transactor, *recordingDriver) {
+	t.Helper()
+	registerDriver.Do(func() {
+		sql.Register("transactor-recording", testDriver)
+	})
+
+	testDriverMu.Lock()
+	t.Cleanup(testDriverMu.Unlock)
+	testDriver.mu.Lock()
+	testDriver.log = nil
+	testDriver.mu.Unlock()
+
+	db, err := sqlx.Open("transactor-recording", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	tr, _ := New(db, WithNestedTransactionStrategy(NestedTransactionsSavepoints))
+	return tr, testDriver
+}
+
+// hook ต้องถูกจดลง log เดียวกับคำสั่ง SQL เพื่อให้รู้ว่ารันก่อนหรือหลัง COMMIT
+func recordHook(d *recordingDriver, name string) PostCommitHook {
+	return func(ctx context.Context) error {
+		d.record("hook " + name)
+		return nil
+	}
+}
+
+func TestNestedTransactionHooksRunAfterOutermostCommit(t *testing.T) {
+	tr, d := newTestTransactor(t)
+	ctx := context.Background()
+
+	err := tr.WithinTransaction(ctx, func(ctx context.Context, register func(PostCommitHook)) error {
+		register(recordHook(d, "outer"))
+		return tr.WithinTransaction(ctx, func(ctx context.Context, register func(PostCommitHook)) error {
+			register(recordHook(d, "inner"))
+			return RegisterPostCommitHook(ctx, recordHook(d, "inner via ctx"))
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tr.Drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"BEGIN",
+		"SAVEPOINT sp_1",
+		"RELEASE SAVEPOINT sp_1",
+		"COMMIT",
+		"hook outer",
+		"hook inner",
+		"hook inner via ctx",
+	}
+	if got := d.entries(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("log = %q, want %q", got, want)
+	}
+}
+
+func TestNestedTransactionHooksDroppedWhenOuterRollsBack(t *testing.T) {
+	tr, d := newTestTransactor(t)
+	ctx := context.Background()
+	errOuter := errors.New("outer failed")
+
+	err := tr.WithinTransaction(ctx, func(ctx context.Context, register func(PostCommitHook)) error {
+		if err := tr.WithinTransaction(ctx, func(ctx context.Context, register func(PostCommitHook)) error {
+			register(recordHook(d, "inner"))
+			return nil
+		}); err != nil {
+			return err
+		}
+		return errOuter
+	})
+	if !errors.Is(err, errOuter) {
+		t.Fatalf("error = %v, want %v", err, errOuter)
+	}
+	if _, err := tr.Drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, entry := range d.entries() {
+		if strings.HasPrefix(entry, "hook") {
+			t.Fatalf("hook ran after outer rollback: %q", d.entries())
+		}
+	}
+}
+
+func TestNestedTransactionHooksDroppedWhenInnerRollsBack(t *testing.T) {
+	tr, d := newTestTransactor(t)
+	ctx := context.Background()
+	errInner := errors.New("inner failed")
+
+	err := tr.WithinTransaction(ctx, func(ctx context.Context, register func(PostCommitHook)) error {
+		register(recordHook(d, "outer"))
+		err := tr.WithinTransaction(ctx, func(ctx context.Context, register func(PostCommitHook)) error {
+			register(recordHook(d, "inner"))
+			return errInner
+		})
+		if !errors.Is(err, errInner) {
+			t.Errorf("inner error = %v, want %v", err, errInner)
+		}
+		// outer ทำงานต่อได้หลัง savepoint ถูก rollback
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tr.Drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"BEGIN",
+		"SAVEPOINT sp_1",
+		"ROLLBACK TO SAVEPOINT sp_1",
+		"COMMIT",
+		"hook outer",
+	}
+	if got := d.entries(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("log = %q, want %q", got, want)
+	}
+}
+
+// context ที่ถูกแยกออกจาก transaction ด้วย WithoutTransaction เปิด transaction ใหม่ที่ commit และรัน hook ของตัวเอง
+func TestWithoutTransactionStartsIndependentTransaction(t *testing.T) {
+	tr, d := newTestTransactor(t)
+	ctx := context.Background()
+
+	err := tr.WithinTransaction(ctx, func(ctx context.Context, register func(PostCommitHook)) error {
+		return tr.WithinTransaction(WithoutTransaction(ctx), func(ctx context.Context, register func(PostCommitHook)) error {
+			register(recordHook(d, "independent"))
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tr.Drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var commits, hooks int
+	for _, entry := range d.entries() {
+		switch entry {
+		case "COMMIT":
+			commits++
+		case "hook independent":
+			hooks++
+		case "SAVEPOINT sp_1":
+			t.Fatalf("log = %q, want no savepoint", d.entries())
+		}
+	}
+	if commits != 2 || hooks != 1 {
+		t.Fatalf("log = %q, want two commits and the independent hook", d.entries())
+	}
+}
@@ -1,6 +1,27 @@
 package customercontract
 
+import (
+	"fmt"
+	"go-mma/shared/common/auth"
+)
+
 type ReleaseCreditCommand struct {
 	CustomerID   int64 `json:"customer_id"`
 	CreditAmount int   `json:"credit_amount"`
 }
+
+// RequiredPermissions ใช้ตรวจสิทธิ์ใน mediator.Authorization ทั้งใน process เดียวกันและเมื่อถูกเรียกผ่าน mediator.Server
+func (c *ReleaseCreditCommand) RequiredPermissions() []auth.Permission {
+	return []auth.Permission{PermissionReleaseCredit}
+}
+
+// Validate ถูกเรียกโดย mediator.Validation ก่อนถึง handler รวมถึงเมื่อถูกเรียกผ่าน mediator.Server
+func (c *ReleaseCreditCommand) Validate() error {
+	if c.CustomerID <= 0 {
+		return fmt.Errorf("customer_id is required")
+	}
+	if c.CreditAmount <= 0 {
+		return fmt.Errorf("credit_amount must be greater than 0")
+	}
+	return nil
+}
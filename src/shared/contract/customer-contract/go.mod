@@ -3,3 +3,5 @@ module go-mma/shared/contract/customercontract
 go 1.24.1
 
 replace go-mma/shared/common v0.0.0 => ../../common
+
+require go-mma/shared/common v0.0.0
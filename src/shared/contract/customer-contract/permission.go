@@ -0,0 +1,9 @@
+package customercontract
+
+import "go-mma/shared/common/auth"
+
+// สิทธิ์ของ command ที่โมดูลอื่นเรียกใช้ role ที่สร้างหรือยกเลิก order ต้องได้รับสิทธิ์เหล่านี้ด้วย
+const (
+	PermissionReserveCredit auth.Permission = "customer:reserve-credit"
+	PermissionReleaseCredit auth.Permission = "customer:release-credit"
+)
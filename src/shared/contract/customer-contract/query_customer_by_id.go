@@ -1,9 +1,28 @@
 package customercontract
 
+import (
+	"fmt"
+	"time"
+)
+
 type GetCustomerByIDQuery struct {
 	ID int64 `json:"id"`
 }
 
+// CacheKey ทำให้ผลลัพธ์ของ query นี้ถูกเก็บใน cache ของ mediator
+func (q *GetCustomerByIDQuery) CacheKey() string {
+	return CustomerCacheKey(q.ID)
+}
+
+func (q *GetCustomerByIDQuery) CacheTTL() time.Duration {
+	return 5 * time.Minute
+}
+
+// CustomerCacheKey ใช้ invalidate ข้อมูลลูกค้าใน cache เมื่อข้อมูลเปลี่ยน
+func CustomerCacheKey(id int64) string {
+	return fmt.Sprintf("customer:%d", id)
+}
+
 type GetCustomerByIDQueryResult struct {
 	ID     int64  `json:"id"`
 	Email  string `json:"email"`
@@ -0,0 +1,3 @@
+module go-mma/shared/contract/ordercontract
+
+go 1.24.1
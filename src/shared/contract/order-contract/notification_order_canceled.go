@@ -0,0 +1,9 @@
+package ordercontract
+
+// OrderCanceledNotification ถูก publish ผ่าน mediator หลังจาก transaction ที่ยกเลิก order commit แล้ว
+// โมดูลที่สนใจลงทะเบียน handler ด้วย mediator.RegisterNotificationHandler ได้หลายตัว
+type OrderCanceledNotification struct {
+	OrderID      int64 `json:"order_id"`
+	CustomerID   int64 `json:"customer_id"`
+	RefundAmount int   `json:"refund_amount"`
+}
@@ -3,6 +3,7 @@ package messaging
 import (
 	"go-mma/shared/common/eventbus"
 	"go-mma/shared/common/idgen"
+	"strconv"
 	"time"
 )
 
@@ -10,6 +11,19 @@ const (
 	CustomerCreatedIntegrationEventName eventbus.EventName = "CustomerCreated"
 )
 
+// เมื่อเปลี่ยน field ของ CustomerCreatedIntegrationEvent ให้ขึ้น version ใหม่
+// เพิ่ม WithSchema ของ version ใหม่ และ WithUpcaster ที่แปลง payload จาก version ก่อนหน้า
+// ห้ามแก้ schema ของ version เดิม เพราะ event เก่ายังถูกเก็บอยู่ใน outbox และ event log
+// test เทียบ field กับไฟล์ใน testdata/schemas ซึ่งสร้างไฟล์ของ version ใหม่ได้ด้วย go test -update
+func init() {
+	eventbus.MustRegister(CustomerCreatedIntegrationEventName, 1,
+		func() eventbus.Event {
+			return &CustomerCreatedIntegrationEvent{}
+		},
+		eventbus.WithSchema(1, "customer_id", "email"),
+	)
+}
+
 type CustomerCreatedIntegrationEvent struct {
 	eventbus.BaseEvent
 	CustomerID int64  `json:"customer_id"`
@@ -27,3 +41,8 @@ func NewCustomerCreatedIntegrationEvent(customerID int64, email string) *Custome
 		Email:      email,
 	}
 }
+
+// PartitionKey ทำให้ event ของลูกค้าคนเดียวกันถูกส่งตามลำดับเมื่อใช้ worker pool
+func (e *CustomerCreatedIntegrationEvent) PartitionKey() string {
+	return strconv.FormatInt(e.CustomerID, 10)
+}
@@ -0,0 +1,7 @@
+package messaging
+
+import "testing"
+
+func TestCustomerCreatedIntegrationEventSchema(t *testing.T) {
+	checkGoldenSchema(t, CustomerCreatedIntegrationEventName, &CustomerCreatedIntegrationEvent{})
+}
@@ -5,3 +5,12 @@ go 1.24.1
 replace go-mma/shared/common v0.0.0 => ../common
 
 require go-mma/shared/common v0.0.0
+
+require (
+	github.com/fxamacker/cbor/v2 v2.7.0 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	go.elastic.co/ecszap v1.0.3 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	go.uber.org/zap v1.27.0 // indirect
+)
@@ -0,0 +1,58 @@
+package messaging
+
+import (
+	"flag"
+	"fmt"
+	"go-mma/shared/common/eventbus"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// -update สร้างไฟล์ golden ของ version ใหม่เท่านั้น ไม่เขียนทับไฟล์ของ version เดิม
+var update = flag.Bool("update", false, "write golden schema files for new event versions")
+
+// checkGoldenSchema เทียบ field ของ event กับไฟล์ testdata/schemas/<name>.v<version>.golden
+// ไฟล์ของทุก version ต้องอยู่ครบ และห้ามแก้ไฟล์ของ version ที่มีอยู่แล้ว
+// ถ้าเพิ่มหรือลบ field ต้องขึ้น version ใหม่ (พร้อม upcaster) แล้วรัน go test -update เพื่อสร้างไฟล์ของ version นั้น
+func checkGoldenSchema(t *testing.T, name eventbus.EventName, event eventbus.Event) {
+	t.Helper()
+
+	version, err := eventbus.DefaultRegistry.Version(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for v := 1; v < version; v++ {
+		if _, err := os.Stat(goldenPath(name, v)); err != nil {
+			t.Errorf("missing golden schema of %s version %d: %v", name, v, err)
+		}
+	}
+
+	actual := eventbus.PayloadFields(event)
+	path := goldenPath(name, version)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && *update {
+		if err := os.WriteFile(path, []byte(strings.Join(actual, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("missing golden schema of %s version %d (run go test -update after bumping the version): %v", name, version, err)
+	}
+
+	golden := strings.Fields(string(data))
+	slices.Sort(golden)
+	slices.Sort(actual)
+	if !slices.Equal(golden, actual) {
+		t.Fatalf("%s version %d has fields %v, golden schema has %v; bump the version and add an upcaster instead of changing the event",
+			name, version, actual, golden)
+	}
+}
+
+func goldenPath(name eventbus.EventName, version int) string {
+	return filepath.Join("testdata", "schemas", fmt.Sprintf("%s.v%d.golden", name, version))
+}